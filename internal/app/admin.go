@@ -0,0 +1,310 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/config"
+	_middleware "go-modular/internal/pkg/middleware"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// Errors
+var (
+	// errInvalidID is returned for a malformed :id path parameter
+	errInvalidID = errors.New("invalid id")
+	// errInvalidQuery is returned for a malformed event store query
+	errInvalidQuery = errors.New("invalid event store query")
+)
+
+// Version is the application version, set at build time with
+// -ldflags "-X go-modular/internal/app.Version=1.2.3"
+var Version = "dev"
+
+// registerAdminRoutes mounts the admin API under /admin when admin.token is set
+func (a *App) registerAdminRoutes() {
+	token := config.GetString("admin.token")
+	if token == "" {
+		a.logger.Info("Admin API disabled, admin.token is empty")
+		return
+	}
+
+	a.admin = _middleware.Group(a.r, "/admin", _middleware.AdminToken(token))
+	a.admin.GET("/modules", a.adminModules)
+	a.admin.GET("/routes", a.adminRoutes)
+	a.admin.GET("/events", a.adminEvents)
+	a.admin.GET("/requests", a.adminRequests)
+	a.admin.GET("/build", a.adminBuild)
+	a.admin.GET("/dead-letters", a.adminDeadLetters)
+	a.admin.GET("/dead-letters/:id", a.adminDeadLetter)
+	a.admin.POST("/dead-letters/:id/replay", a.adminReplayDeadLetter)
+	a.admin.DELETE("/dead-letters/:id", a.adminDiscardDeadLetter)
+	if config.GetBool("event_bus.store.enabled") {
+		a.admin.GET("/event-store", a.adminStoredEvents)
+		a.admin.POST("/event-store/replay", a.adminReplayStored)
+	}
+}
+
+// adminModules lists the loaded modules with their dependencies and state
+func (a *App) adminModules(c echo.Context) error {
+	modules := make([]map[string]interface{}, len(a.modules))
+	for i, module := range a.modules {
+		dependencies := module.Dependencies()
+		if dependencies == nil {
+			dependencies = []string{}
+		}
+		modules[i] = map[string]interface{}{
+			"name":         module.Name(),
+			"dependencies": dependencies,
+			"state":        a.state(module.Name()),
+		}
+	}
+	return c.JSON(http.StatusOK, modules)
+}
+
+// adminRoutes lists every route with the middleware wrapping it, outermost first
+func (a *App) adminRoutes(c echo.Context) error {
+	routes := make([]map[string]interface{}, 0)
+	for _, route := range a.Routes() {
+		// catch-all routes echo adds for every group
+		if strings.HasSuffix(route.Name, "(*Group).Use.func1") {
+			continue
+		}
+
+		chain := append([]string{}, a.middleware...)
+		chain = append(chain, _middleware.Chain(a.r, route.Path)...)
+		routes = append(routes, map[string]interface{}{
+			"method":     route.Method,
+			"path":       route.Path,
+			"handler":    route.Name,
+			"middleware": chain,
+		})
+	}
+	return c.JSON(http.StatusOK, routes)
+}
+
+// adminEvents lists the event bus subscriptions per event type
+func (a *App) adminEvents(c echo.Context) error {
+	subscriptions := a.event.Subscriptions()
+
+	eventTypes := make([]string, 0, len(subscriptions))
+	for eventType := range subscriptions {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+
+	events := make([]map[string]interface{}, len(eventTypes))
+	for i, eventType := range eventTypes {
+		events[i] = map[string]interface{}{
+			"type":     eventType,
+			"handlers": subscriptions[eventType],
+		}
+	}
+	return c.JSON(http.StatusOK, events)
+}
+
+// adminRequests lists the command and query types that have a handler
+func (a *App) adminRequests(c echo.Context) error {
+	return c.JSON(http.StatusOK, a.dispatcher.Handlers())
+}
+
+// adminBuild reports the version and build information of the binary
+func (a *App) adminBuild(c echo.Context) error {
+	build := map[string]interface{}{
+		"version":    Version,
+		"go_version": runtime.Version(),
+		"started_at": a.startedAt,
+		"uptime":     time.Since(a.startedAt).Round(time.Second).String(),
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		build["module"] = info.Main.Path
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				build["revision"] = setting.Value
+			case "vcs.time":
+				build["revision_time"] = setting.Value
+			case "vcs.modified":
+				build["modified"] = setting.Value == "true"
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, build)
+}
+
+// adminDeadLetters lists dead-lettered events, oldest first, paged with
+// ?offset= and ?limit=
+func (a *App) adminDeadLetters(c echo.Context) error {
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	letters, err := a.deadLetters.List(c.Request().Context(), offset, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, letters)
+}
+
+// adminDeadLetter shows a single dead-lettered event
+func (a *App) adminDeadLetter(c echo.Context) error {
+	letter, err := a.deadLetterParam(c)
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+	return c.JSON(http.StatusOK, letter)
+}
+
+// adminReplayDeadLetter delivers a dead-lettered event to its subscriber
+// again and discards it when the handler succeeds
+func (a *App) adminReplayDeadLetter(c echo.Context) error {
+	ctx := c.Request().Context()
+	letter, err := a.deadLetterParam(c)
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+
+	if err := a.event.Replay(ctx, letter); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	}
+	if err := a.deadLetters.Delete(ctx, letter.ID); err != nil {
+		return deadLetterError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Event replayed"})
+}
+
+// adminDiscardDeadLetter deletes a dead-lettered event without replaying it
+func (a *App) adminDiscardDeadLetter(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return deadLetterError(c, errInvalidID)
+	}
+	if err := a.deadLetters.Delete(c.Request().Context(), id); err != nil {
+		return deadLetterError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// deadLetterParam loads the dead letter named by the :id parameter
+func (a *App) deadLetterParam(c echo.Context) (*bus.DeadLetter, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, errInvalidID
+	}
+	return a.deadLetters.Get(c.Request().Context(), id)
+}
+
+// deadLetterError maps dead letter store errors to responses
+func deadLetterError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errInvalidID):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid dead letter ID"})
+	case errors.Is(err, bus.ErrDeadLetterNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// storedEventsRequest selects stored events, from the query string of
+// /event-store or the body of /event-store/replay
+type storedEventsRequest struct {
+	Subscriber    string   `json:"subscriber"`
+	Types         []string `json:"types"`
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+	CorrelationID string   `json:"correlation_id"`
+	After         uint64   `json:"after"`
+	Limit         int      `json:"limit"`
+}
+
+// query converts the request to an event store query; from and to are
+// RFC 3339 times
+func (r storedEventsRequest) query() (bus.EventQuery, error) {
+	query := bus.EventQuery{
+		Types:         r.Types,
+		CorrelationID: r.CorrelationID,
+		After:         r.After,
+		Limit:         r.Limit,
+	}
+	var err error
+	if r.From != "" {
+		if query.From, err = time.Parse(time.RFC3339, r.From); err != nil {
+			return query, fmt.Errorf("%w: from: %v", errInvalidQuery, err)
+		}
+	}
+	if r.To != "" {
+		if query.To, err = time.Parse(time.RFC3339, r.To); err != nil {
+			return query, fmt.Errorf("%w: to: %v", errInvalidQuery, err)
+		}
+	}
+	return query, nil
+}
+
+// adminStoredEvents lists recorded events, filtered with ?type= (repeatable),
+// ?from=, ?to=, ?correlation_id= and paged with ?after= and ?limit=
+func (a *App) adminStoredEvents(c echo.Context) error {
+	after, _ := strconv.ParseUint(c.QueryParam("after"), 10, 64)
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	query, err := storedEventsRequest{
+		Types:         c.QueryParams()["type"],
+		From:          c.QueryParam("from"),
+		To:            c.QueryParam("to"),
+		CorrelationID: c.QueryParam("correlation_id"),
+		After:         after,
+		Limit:         limit,
+	}.query()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	events, err := a.eventStore.Query(c.Request().Context(), query)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, events)
+}
+
+// adminReplayStored delivers the recorded events matching the request body
+// to one subscriber, for example to rebuild a projection
+func (a *App) adminReplayStored(c echo.Context) error {
+	var request storedEventsRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if request.Subscriber == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "subscriber is required"})
+	}
+	query, err := request.query()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	replayed, err := a.event.ReplayStored(c.Request().Context(), request.Subscriber, query)
+	switch {
+	case errors.Is(err, bus.ErrUnknownSubscriber):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case err != nil:
+		return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{"error": err.Error(), "replayed": replayed})
+	}
+	return c.JSON(http.StatusOK, map[string]int{"replayed": replayed})
+}
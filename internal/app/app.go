@@ -1,27 +1,88 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/bus/gormbus"
+	simplecache "go-modular/internal/pkg/cache"
+	"go-modular/internal/pkg/cache/rediscache"
 	"go-modular/internal/pkg/config"
+	"go-modular/internal/pkg/container"
 	"go-modular/internal/pkg/database"
+	"go-modular/internal/pkg/health"
 	"go-modular/internal/pkg/logger"
+	"go-modular/internal/pkg/metrics"
+	_middleware "go-modular/internal/pkg/middleware"
+	"go-modular/internal/pkg/migration"
+	"go-modular/internal/pkg/outbox"
 	"go-modular/internal/pkg/server"
 	_validator "go-modular/internal/pkg/validator"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
+// requiredKeys are the core settings the application cannot start without
+var requiredKeys = []string{
+	"server.app_name",
+	"server.mode",
+	"server.port",
+	"server.http_timeout",
+	"server.api_version",
+	"database.db_driver",
+	"database.db_host",
+	"database.db_port",
+	"database.db_name",
+	"database.db_username",
+	"database.db_password",
+	"pool.conn_idle",
+	"pool.conn_max",
+	"pool.conn_lifetime",
+}
+
 // App represents the application
 type App struct {
-	db      *gorm.DB
-	server  *server.ServerContext
-	modules []Module
-	r       *echo.Echo
-	logger  *logger.Logger
+	db        *gorm.DB
+	server    *server.ServerContext
+	modules   []Module
+	r         *echo.Echo
+	logger    *logger.Logger
+	event     *bus.EventBus
+	relay     *outbox.Relay
+	services  *container.Container
+	liveness  *health.Registry
+	readiness *health.Registry
+	metrics   *metrics.Registry
+
+	// deadLetters holds events whose handlers failed every retry
+	deadLetters bus.DeadLetterStore
+	// eventStore records every published event, nil unless enabled
+	eventStore bus.EventStore
+	// dispatcher routes commands and queries between modules
+	dispatcher *bus.Dispatcher
+	// cache is shared by the modules through the service container
+	cache simplecache.Cache
+
+	// admin is the protected route group for introspection endpoints
+	admin *echo.Group
+	// middleware lists the names of the router-level middleware in order
+	middleware []string
+	startedAt  time.Time
+
+	statesMu sync.RWMutex
+	states   map[string]string
 }
 
 // NewApp creates a new application
@@ -32,8 +93,10 @@ func NewApp(cfg *logger.Config) (*App, error) {
 	}
 	defer appLogger.Sync()
 	return &App{
-		modules: make([]Module, 0),
-		logger:  appLogger,
+		modules:   make([]Module, 0),
+		logger:    appLogger,
+		states:    make(map[string]string),
+		startedAt: time.Now(),
 	}, nil
 }
 
@@ -44,12 +107,20 @@ func (a *App) SetRouter() *echo.Echo {
 // RegisterModule registers a module with the application
 func (a *App) RegisterModule(module Module) {
 	a.modules = append(a.modules, module)
+	a.setState(module.Name(), ModuleRegistered)
 	a.logger.Info("Registered module: %s", module.Name())
 }
 
-// Initialize initializes the application
-func (a *App) Initialize() error {
-	a.logger.Info("Initializing application...")
+// Connect orders the modules by dependency and opens the database. It is
+// all that running migrations needs; Initialize calls it first.
+func (a *App) Connect() error {
+	if a.db != nil {
+		return nil
+	}
+
+	if err := a.orderModules(); err != nil {
+		return err
+	}
 
 	// Initialize database
 	var err *error
@@ -62,14 +133,103 @@ func (a *App) Initialize() error {
 	// Set database instance for all modules
 	database.DB = a.db
 
+	return nil
+}
+
+// orderModules sorts the modules so that dependencies are set up first
+func (a *App) orderModules() error {
+	modules, err := sortModules(a.modules)
+	if err != nil {
+		a.logger.Error("Failed to resolve module dependencies", "error", err.Error())
+		return err
+	}
+	a.modules = modules
+	return nil
+}
+
+// Initialize initializes the application
+func (a *App) Initialize() error {
+	return a.initialize(true)
+}
+
+// Inspect wires the modules and routes like Initialize, without opening the
+// database: the event bus keeps events in memory and has no stores, and no
+// outbox relay is created. The application can be looked at, as the routes
+// command does, but not started.
+func (a *App) Inspect() error {
+	return a.initialize(false)
+}
+
+// initialize builds the application, with its database-backed parts when
+// connect is set
+func (a *App) initialize(connect bool) error {
+	a.logger.Info("Initializing application...")
+
 	// event bus initialization
-	event := bus.NewEventBus()
+	var opts []bus.Option
+	if connect {
+		if err := a.Connect(); err != nil {
+			return err
+		}
+		dbOpts, err := a.databaseOptions()
+		if err != nil {
+			return err
+		}
+		opts = dbOpts
+	} else if err := a.orderModules(); err != nil {
+		return err
+	}
+	opts = append(opts,
+		bus.WithWorkers(config.GetInt("event_bus.workers")),
+		bus.WithHandlerTimeout(time.Duration(config.GetInt("event_bus.handler_timeout"))*time.Second),
+		bus.WithLogger(a.logger.WithPrefix("bus")),
+		bus.WithDefaultRetry(bus.RetryPolicy{
+			MaxAttempts:    config.GetInt("event_bus.retry.max_attempts"),
+			InitialBackoff: time.Duration(config.GetInt("event_bus.retry.initial_backoff")) * time.Millisecond,
+			MaxBackoff:     time.Duration(config.GetInt("event_bus.retry.max_backoff")) * time.Millisecond,
+			Multiplier:     config.GetFloat64("event_bus.retry.multiplier"),
+			Jitter:         config.GetFloat64("event_bus.retry.jitter"),
+		}),
+	)
+	a.event = bus.NewEventBus(opts...)
+
+	// outbox relay publishing events recorded by the modules
+	if connect {
+		a.relay = outbox.NewRelay(
+			a.db,
+			a.event,
+			a.logger.WithPrefix("outbox"),
+			time.Duration(config.GetInt("outbox.poll_interval"))*time.Second,
+			config.GetInt("outbox.batch_size"),
+			config.GetInt("outbox.max_attempts"),
+		)
+	}
+
+	// service container shared by all modules
+	a.services = container.New()
+
+	// command and query dispatcher for request/reply calls between modules
+	a.dispatcher = bus.NewDispatcher(time.Duration(config.GetInt("event_bus.request_timeout")) * time.Second)
+	if err := container.Provide(a.services, a.dispatcher); err != nil {
+		return err
+	}
+
+	// cache shared by the modules; each takes a WithPrefix view of its own
+	var err error
+	if a.cache, err = a.newCache(); err != nil {
+		a.logger.Error("Failed to create cache", "error", err.Error())
+		return err
+	}
+	if err := container.Provide(a.services, a.cache); err != nil {
+		return err
+	}
 
 	// initialize router
 	a.r = a.SetRouter()
-	a.r.Use(middleware.Logger())
-	a.r.Use(middleware.Recover())
-	a.r.Use(middleware.CORS())
+	a.use("Logger", middleware.Logger())
+	a.use("Recover", middleware.Recover())
+	a.use("CORS", middleware.CORS())
+	a.use("Correlation", _middleware.Correlation())
 
 	// validate request
 	a.r.Validator = _validator.NewCustomValidator()
@@ -78,31 +238,41 @@ func (a *App) Initialize() error {
 	for _, module := range a.modules {
 		a.logger.Info("Initializing module: %s", module.Name())
 
+		// Decode the module's own configuration section
+		if configurable, ok := module.(Configurable); ok {
+			if err := a.configureModule(module.Name(), configurable.Config()); err != nil {
+				a.logger.Error("Invalid module configuration", "module", module.Name(), "error", err.Error())
+				return err
+			}
+		}
+
 		// Create module-specific logger
 		moduleLogger := a.logger.WithPrefix(module.Name())
-		if err := module.Initialize(a.db, moduleLogger, event); err != nil {
+		if err := module.Initialize(a.db, moduleLogger, a.event.WithSource(module.Name()), a.services); err != nil {
+			a.setState(module.Name(), ModuleFailed)
 			a.logger.Error("Failed to initialize module %s: %v", module.Name(), err)
 			return err
 		}
+		a.setState(module.Name(), ModuleInitialized)
 
 		a.logger.Info("Module initialized: %s", module.Name())
 	}
 
-	// Run migrations for all modules
-	for _, module := range a.modules {
-		err := module.Migrations()
-		if err != nil {
-			a.logger.Error("Failed to run migrations for module %s: %v", module.Name(), err)
-		}
-		a.logger.Info("Migrations completed for module: %s", module.Name())
-	}
-
 	// Initialize HTTP server
 	a.server = a.SetServer()
 
 	// api version
 	version := fmt.Sprintf("/api/v%s", config.GetString("server.api_version"))
 
+	// Health checks
+	a.registerHealthChecks()
+
+	// Prometheus metrics
+	a.registerMetrics()
+
+	// Admin introspection API
+	a.registerAdminRoutes()
+
 	// Register routes for all modules
 	for _, module := range a.modules {
 		a.logger.Info("Registering routes for module: %s", module.Name())
@@ -115,17 +285,429 @@ func (a *App) Initialize() error {
 
 	a.logger.Info("Application initialization completed")
 
-	for _, v := range a.r.Routes() {
-		fmt.Printf("PATH: %v | METHOD: %v\n", v.Path, v.Method)
+	return nil
+}
+
+// databaseOptions configures the event bus parts backed by the database:
+// its transport, buffer overflow, dead letters, schedule and event store
+func (a *App) databaseOptions() ([]bus.Option, error) {
+	transport, err := a.newTransport()
+	if err != nil {
+		a.logger.Error("Failed to create event bus transport", "error", err.Error())
+		return nil, err
+	}
+	opts, err := a.overflowOptions()
+	if err != nil {
+		a.logger.Error("Invalid event bus overflow settings", "error", err.Error())
+		return nil, err
+	}
+
+	a.deadLetters = gormbus.NewDeadLetterStore(a.db)
+	opts = append(opts,
+		bus.WithTransport(transport),
+		bus.WithDeadLetterStore(a.deadLetters),
+		bus.WithScheduleStore(
+			gormbus.NewScheduleStore(a.db),
+			time.Duration(config.GetInt("event_bus.schedule.poll_interval"))*time.Millisecond,
+		),
+	)
+	if config.GetBool("event_bus.store.enabled") {
+		a.eventStore = gormbus.NewEventStore(a.db)
+		opts = append(opts, bus.WithEventStore(a.eventStore))
+	}
+	return opts, nil
+}
+
+// newTransport creates the event bus transport named by event_bus.transport.
+// The database transport shares events between every process using the
+// same database, grouped by event_bus.database.group.
+func (a *App) newTransport() (bus.Transport, error) {
+	switch name := config.GetString("event_bus.transport"); name {
+	case "memory":
+		return bus.NewMemoryTransport(), nil
+	case "database":
+		group := config.GetString("event_bus.database.group")
+		if group == "" {
+			group = config.GetString("server.app_name")
+		}
+		return gormbus.NewTransport(a.db, group,
+			gormbus.WithPollInterval(time.Duration(config.GetInt("event_bus.database.poll_interval"))*time.Millisecond),
+			gormbus.WithBatchSize(config.GetInt("event_bus.database.batch_size")),
+			gormbus.WithSettleDelay(time.Duration(config.GetInt("event_bus.database.settle_delay"))*time.Millisecond),
+			gormbus.WithRetention(time.Duration(config.GetInt("event_bus.database.retention"))*time.Hour),
+			gormbus.WithLogger(a.logger.WithPrefix("transport")),
+		)
+	default:
+		return nil, fmt.Errorf("unknown event bus transport %q", name)
+	}
+}
+
+// newCache builds the cache selected by cache.driver; entries are kept for
+// server.cache_expired minutes unless Set is given a TTL
+func (a *App) newCache() (simplecache.Cache, error) {
+	ttl := time.Duration(config.GetInt("server.cache_expired")) * time.Minute
+	switch name := config.GetString("cache.driver"); name {
+	case "memory":
+		return simplecache.NewMemory(ttl, time.Duration(config.GetInt("server.cache_purged"))*time.Minute), nil
+	case "redis":
+		codec, err := rediscache.ParseCodec(config.GetString("cache.redis.codec"))
+		if err != nil {
+			return nil, err
+		}
+		prefix := config.GetString("cache.redis.key_prefix")
+		if prefix == "" {
+			prefix = config.GetString("server.app_name")
+		}
+		client := redis.NewClient(&redis.Options{
+			Addr:     config.GetString("cache.redis.addr"),
+			Username: config.GetString("cache.redis.username"),
+			Password: config.GetString("cache.redis.password"),
+			DB:       config.GetInt("cache.redis.db"),
+			PoolSize: config.GetInt("cache.redis.pool_size"),
+		})
+		return rediscache.New(client,
+			rediscache.WithKeyPrefix(prefix),
+			rediscache.WithCodec(codec),
+			rediscache.WithDefaultTTL(ttl),
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown cache driver %q", name)
+	}
+}
+
+// overflowOptions configures the event buffer and what happens to events
+// that arrive while it is full
+func (a *App) overflowOptions() ([]bus.Option, error) {
+	policy, err := bus.ParseOverflowPolicy(config.GetString("event_bus.overflow"))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []bus.Option{
+		bus.WithBuffer(config.GetInt("event_bus.buffer_size")),
+		bus.WithOverflow(policy),
+		bus.WithBlockTimeout(time.Duration(config.GetInt("event_bus.block_timeout")) * time.Millisecond),
+	}
+	if policy == bus.Spill {
+		spill, err := bus.OpenSpillFile(config.GetString("event_bus.spill_path"))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, bus.WithSpill(spill))
+	}
+	return opts, nil
+}
+
+// registerHealthChecks sets up the liveness and readiness endpoints with the
+// built-in checks and the checks of every module implementing HealthChecker
+func (a *App) registerHealthChecks() {
+	timeout := time.Duration(config.GetInt("server.health_timeout")) * time.Second
+
+	// liveness only says the process can answer; dependencies go in readiness
+	a.liveness = health.NewRegistry(timeout)
+
+	a.readiness = health.NewRegistry(timeout)
+	a.readiness.Register("database", health.Database(a.db))
+	a.readiness.Register("event_bus", health.EventBus(a.event, config.GetFloat64("health.event_bus_threshold")))
+	if pinger, ok := a.cache.(interface{ Ping(context.Context) error }); ok {
+		a.readiness.Register("cache", pinger.Ping)
+	}
+	for _, module := range a.modules {
+		if checker, ok := module.(HealthChecker); ok {
+			a.readiness.Register(module.Name(), checker.HealthCheck)
+		}
 	}
 
+	a.r.GET("/health", a.liveness.Handler)
+	a.r.GET("/ready", a.readiness.Handler)
+}
+
+// registerMetrics serves the event bus counters on /metrics
+func (a *App) registerMetrics() {
+	a.metrics = metrics.NewRegistry()
+	a.metrics.GaugeFunc("event_bus_pending", "Events waiting for a worker.", func() float64 {
+		return float64(a.event.Pending())
+	})
+	a.metrics.GaugeFunc("event_bus_capacity", "Size of the event buffers.", func() float64 {
+		return float64(a.event.Capacity())
+	})
+	a.metrics.GaugeFunc("event_bus_saturation", "Fill ratio of the fullest event queue.", func() float64 {
+		return a.event.Saturation()
+	})
+	a.metrics.CounterFunc("event_bus_blocked_total", "Events that waited for room in a full buffer.", func() float64 {
+		return float64(a.event.Stats().Blocked)
+	})
+	a.metrics.CounterFunc("event_bus_timed_out_total", "Events rejected after the block timeout.", func() float64 {
+		return float64(a.event.Stats().TimedOut)
+	})
+	a.metrics.CounterFunc("event_bus_dropped_total", "Events dropped by the overflow policy.", func() float64 {
+		return float64(a.event.Stats().Dropped)
+	})
+	a.metrics.CounterFunc("event_bus_spilled_total", "Events written to the spill file.", func() float64 {
+		return float64(a.event.Stats().Spilled)
+	})
+
+	a.r.GET("/metrics", a.metrics.Handler)
+}
+
+// use adds router-level middleware and remembers its name
+func (a *App) use(name string, m echo.MiddlewareFunc) {
+	a.r.Use(m)
+	a.middleware = append(a.middleware, name)
+}
+
+// setState records the lifecycle state of a module
+func (a *App) setState(name, state string) {
+	a.statesMu.Lock()
+	defer a.statesMu.Unlock()
+	a.states[name] = state
+}
+
+// state returns the lifecycle state of a module
+func (a *App) state(name string) string {
+	a.statesMu.RLock()
+	defer a.statesMu.RUnlock()
+	return a.states[name]
+}
+
+// Routes returns the registered HTTP routes sorted by path and method
+func (a *App) Routes() []*echo.Route {
+	routes := a.r.Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// ValidateConfig checks that the core settings are present and that the
+// dependencies of the registered modules resolve, and decodes and validates
+// the configuration section of every registered module
+func (a *App) ValidateConfig() error {
+	var errs []error
+	if missing := config.Missing(requiredKeys...); len(missing) > 0 {
+		errs = append(errs, fmt.Errorf("missing configuration keys: %s", strings.Join(missing, ", ")))
+	}
+	if _, err := sortModules(a.modules); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, module := range a.modules {
+		if configurable, ok := module.(Configurable); ok {
+			if err := a.configureModule(module.Name(), configurable.Config()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// migrationSet is the migrations owned by a core package or a module
+type migrationSet struct {
+	name       string
+	migrations []migration.Migration
+}
+
+// migrationSets returns the core migrations followed by those of every
+// module in dependency order
+func (a *App) migrationSets() []migrationSet {
+	sets := []migrationSet{
+		{name: "outbox", migrations: outbox.Migrations()},
+		{name: "bus", migrations: gormbus.Migrations()},
+	}
+	for _, module := range a.modules {
+		sets = append(sets, migrationSet{name: module.Name(), migrations: module.Migrations()})
+	}
+	return sets
+}
+
+// Migrate applies the pending core migrations, then those of every module
+// in dependency order
+func (a *App) Migrate(ctx context.Context) error {
+	migrator := migration.NewMigrator(a.db)
+	for _, set := range a.migrationSets() {
+		applied, err := migrator.Up(ctx, set.name, set.migrations)
+		for _, m := range applied {
+			a.logger.Info("Applied migration", "module", set.name, "version", m.Version, "name", m.Name)
+		}
+		if err != nil {
+			a.logger.Error("Failed to run migrations", "module", set.name, "error", err.Error())
+			return err
+		}
+		a.logger.Info("Migrations completed", "module", set.name)
+	}
 	return nil
 }
 
-// Start starts the application
-func (a *App) Start() {
+// Rollback rolls the migrations of the named module or core package back
+// to target version
+func (a *App) Rollback(ctx context.Context, name string, target int64) error {
+	var migrations []migration.Migration
+	found := false
+	for _, set := range a.migrationSets() {
+		if set.name == name {
+			migrations, found = set.migrations, true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrUnknownModule, name)
+	}
+
+	rolledBack, err := migration.NewMigrator(a.db).Down(ctx, name, migrations, target)
+	for _, m := range rolledBack {
+		a.logger.Info("Rolled back migration", "module", name, "version", m.Version, "name", m.Name)
+	}
+	if err != nil {
+		a.logger.Error("Failed to roll back migrations", "module", name, "error", err.Error())
+	}
+	return err
+}
+
+// MigrationStatus reports the core and module migrations and whether they are applied
+func (a *App) MigrationStatus(ctx context.Context) ([]migration.Status, error) {
+	migrator := migration.NewMigrator(a.db)
+	statuses := make([]migration.Status, 0)
+	for _, set := range a.migrationSets() {
+		status, err := migrator.Status(ctx, set.name, set.migrations)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status...)
+	}
+	return statuses, nil
+}
+
+// Start starts the application and blocks until it receives a shutdown
+// signal or the server fails, then stops the modules in reverse order
+func (a *App) Start() error {
 	a.logger.Info("Starting server on %s", a.server.Host)
-	a.server.Run()
+	serverErr := a.server.Start()
+
+	// ctx is cancelled as soon as shutdown begins so background work started
+	// by the modules can wind down
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the modules have subscribed, so events from other processes and
+	// scheduled events can flow
+	a.event.Start()
+
+	// publish the events modules recorded in the outbox
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		a.relay.Run(ctx)
+	}()
+
+	started, err := a.startModules(ctx)
+	if err == nil {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+
+		select {
+		case sig := <-signals:
+			a.logger.Info("Shutting down", "signal", sig.String())
+		case err = <-serverErr:
+			a.logger.Error("Server stopped unexpectedly", "error", err.Error())
+		}
+	}
+
+	cancel()
+	<-relayDone
+	if stopErr := a.shutdown(started); stopErr != nil && err == nil {
+		err = stopErr
+	}
+
+	return err
+}
+
+// startModules starts every module implementing Starter in dependency order
+// and returns the names of the modules that were started
+func (a *App) startModules(ctx context.Context) (map[string]bool, error) {
+	started := make(map[string]bool, len(a.modules))
+	for _, module := range a.modules {
+		starter, ok := module.(Starter)
+		if !ok {
+			continue
+		}
+
+		a.logger.Info("Starting module", "module", module.Name())
+		if err := starter.Start(ctx); err != nil {
+			a.setState(module.Name(), ModuleFailed)
+			a.logger.Error("Failed to start module", "module", module.Name(), "error", err.Error())
+			return started, err
+		}
+		a.setState(module.Name(), ModuleStarted)
+		started[module.Name()] = true
+	}
+
+	return started, nil
+}
+
+// shutdown stops the HTTP server, then the started modules in reverse
+// order and finally the event bus, all within server.shutdown_timeout
+func (a *App) shutdown(started map[string]bool) error {
+	timeout := time.Duration(config.GetInt("server.shutdown_timeout")) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	if err := a.server.Shutdown(ctx); err != nil {
+		a.logger.Error("Server was unable to gracefully shutdown", "error", err.Error())
+		errs = append(errs, err)
+	}
+
+	for i := len(a.modules) - 1; i >= 0; i-- {
+		module := a.modules[i]
+		stopper, ok := module.(Stopper)
+		if !ok {
+			continue
+		}
+
+		// a module whose Start never ran has nothing to stop
+		if _, isStarter := module.(Starter); isStarter && !started[module.Name()] {
+			continue
+		}
+
+		a.logger.Info("Stopping module", "module", module.Name())
+		if err := stopper.Stop(ctx); err != nil {
+			a.setState(module.Name(), ModuleFailed)
+			a.logger.Error("Failed to stop module", "module", module.Name(), "error", err.Error())
+			errs = append(errs, err)
+			continue
+		}
+		a.setState(module.Name(), ModuleStopped)
+	}
+
+	if err := a.event.Shutdown(ctx); err != nil {
+		a.logger.Error("Event bus did not drain before the shutdown timeout", "error", err.Error())
+		errs = append(errs, err)
+	}
+	if closer, ok := a.cache.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			a.logger.Error("Failed to close the cache", "error", err.Error())
+			errs = append(errs, err)
+		}
+	}
+	a.logger.Info("Application stopped")
+
+	return errors.Join(errs...)
+}
+
+// configureModule decodes [modules.<name>] into cfg and validates it
+func (a *App) configureModule(name string, cfg interface{}) error {
+	if err := config.UnmarshalKey("modules."+name, cfg); err != nil {
+		return fmt.Errorf("modules.%s: %w", name, err)
+	}
+	if err := _validator.NewCustomValidator().Validate(cfg); err != nil {
+		return fmt.Errorf("modules.%s: %w", name, err)
+	}
+	return nil
 }
 
 // setup database model
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateConfigChecksDependencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		modules []Module
+		want    error
+	}{
+		{"missing", []Module{&testModule{name: "auth", deps: []string{"user"}}}, ErrMissingDependency},
+		{"duplicate", []Module{&testModule{name: "user"}, &testModule{name: "user"}}, ErrDuplicateModule},
+		{"cycle", []Module{
+			&testModule{name: "auth", deps: []string{"user"}},
+			&testModule{name: "user", deps: []string{"auth"}},
+		}, ErrDependencyCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{modules: tt.modules}
+			if err := a.ValidateConfig(); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	a := &App{modules: []Module{&testModule{name: "user"}, &testModule{name: "auth", deps: []string{"user"}}}}
+	if err := a.ValidateConfig(); errors.Is(err, ErrMissingDependency) {
+		t.Errorf("unexpected dependency error %v", err)
+	}
+}
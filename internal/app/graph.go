@@ -0,0 +1,93 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors
+var (
+	ErrDuplicateModule   = errors.New("duplicate module")
+	ErrMissingDependency = errors.New("missing module dependency")
+	ErrDependencyCycle   = errors.New("module dependency cycle")
+)
+
+// sortModules returns the modules in dependency order: every module comes
+// after the modules it depends on. Modules without a relation between them
+// keep their registration order so startup stays deterministic.
+func sortModules(modules []Module) ([]Module, error) {
+	index := make(map[string]int, len(modules))
+	for i, module := range modules {
+		if _, exists := index[module.Name()]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateModule, module.Name())
+		}
+		index[module.Name()] = i
+	}
+
+	// count unresolved dependencies and remember who is waiting on whom
+	pending := make([]int, len(modules))
+	dependents := make([][]int, len(modules))
+	for i, module := range modules {
+		for _, dep := range module.Dependencies() {
+			j, exists := index[dep]
+			if !exists {
+				return nil, fmt.Errorf("%w: module %s requires %s", ErrMissingDependency, module.Name(), dep)
+			}
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	sorted := make([]Module, 0, len(modules))
+	done := make([]bool, len(modules))
+	for len(sorted) < len(modules) {
+		next := -1
+		for i := range modules {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, describeCycle(modules, done, index))
+		}
+
+		done[next] = true
+		sorted = append(sorted, modules[next])
+		for _, i := range dependents[next] {
+			pending[i]--
+		}
+	}
+
+	return sorted, nil
+}
+
+// describeCycle walks the unresolved modules until one repeats and returns
+// the cycle as "a -> b -> a"
+func describeCycle(modules []Module, done []bool, index map[string]int) string {
+	start := -1
+	for i := range modules {
+		if !done[i] {
+			start = i
+			break
+		}
+	}
+
+	seen := make(map[int]int)
+	path := make([]string, 0)
+	for current := start; ; {
+		if pos, exists := seen[current]; exists {
+			return strings.Join(append(path[pos:], modules[current].Name()), " -> ")
+		}
+		seen[current] = len(path)
+		path = append(path, modules[current].Name())
+
+		for _, dep := range modules[current].Dependencies() {
+			if j := index[dep]; !done[j] {
+				current = j
+				break
+			}
+		}
+	}
+}
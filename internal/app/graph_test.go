@@ -0,0 +1,92 @@
+package app
+
+import (
+	"errors"
+	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/container"
+	"go-modular/internal/pkg/logger"
+	"go-modular/internal/pkg/migration"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"gorm.io/gorm"
+)
+
+type testModule struct {
+	name string
+	deps []string
+}
+
+func (m *testModule) Name() string           { return m.name }
+func (m *testModule) Dependencies() []string { return m.deps }
+func (m *testModule) Initialize(*gorm.DB, *logger.Logger, *bus.EventBus, *container.Container) error {
+	return nil
+}
+func (m *testModule) RegisterRoutes(*echo.Echo, string) {}
+func (m *testModule) Migrations() []migration.Migration { return nil }
+func (m *testModule) Logger() *logger.Logger            { return nil }
+
+func names(modules []Module) string {
+	result := make([]string, len(modules))
+	for i, module := range modules {
+		result[i] = module.Name()
+	}
+	return strings.Join(result, ",")
+}
+
+func TestSortModules(t *testing.T) {
+	modules := []Module{
+		&testModule{name: "auth", deps: []string{"user"}},
+		&testModule{name: "report", deps: []string{"auth", "user"}},
+		&testModule{name: "user"},
+		&testModule{name: "audit"},
+	}
+
+	sorted, err := sortModules(modules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := names(sorted); got != "user,auth,report,audit" {
+		t.Errorf("unexpected order: %s", got)
+	}
+}
+
+func TestSortModulesMissingDependency(t *testing.T) {
+	modules := []Module{
+		&testModule{name: "auth", deps: []string{"user"}},
+	}
+
+	if _, err := sortModules(modules); !errors.Is(err, ErrMissingDependency) {
+		t.Errorf("expected missing dependency error, got %v", err)
+	}
+}
+
+func TestSortModulesCycle(t *testing.T) {
+	modules := []Module{
+		&testModule{name: "user"},
+		&testModule{name: "a", deps: []string{"b"}},
+		&testModule{name: "b", deps: []string{"c", "user"}},
+		&testModule{name: "c", deps: []string{"a"}},
+	}
+
+	_, err := sortModules(modules)
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Errorf("cycle not described: %v", err)
+	}
+}
+
+func TestSortModulesDuplicate(t *testing.T) {
+	modules := []Module{
+		&testModule{name: "user"},
+		&testModule{name: "user"},
+	}
+
+	if _, err := sortModules(modules); !errors.Is(err, ErrDuplicateModule) {
+		t.Errorf("expected duplicate module error, got %v", err)
+	}
+}
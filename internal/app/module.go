@@ -1,27 +1,71 @@
 package app
 
 import (
+	"context"
 	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/container"
 	"go-modular/internal/pkg/logger"
+	"go-modular/internal/pkg/migration"
 
 	"github.com/labstack/echo"
 	"gorm.io/gorm"
 )
 
+// Module lifecycle states reported by the admin API
+const (
+	ModuleRegistered  = "registered"
+	ModuleInitialized = "initialized"
+	ModuleStarted     = "started"
+	ModuleStopped     = "stopped"
+	ModuleFailed      = "failed"
+)
+
 // Module represents an application module
 type Module interface {
 	// Name returns the name of the module
 	Name() string
 
-	// Initialize initializes the module
-	Initialize(db *gorm.DB, logger *logger.Logger, event *bus.EventBus) error
+	// Dependencies returns the names of the modules that must be initialized first
+	Dependencies() []string
+
+	// Initialize initializes the module; services holds what other modules
+	// provide and is where this module provides its own ports
+	Initialize(db *gorm.DB, logger *logger.Logger, event *bus.EventBus, services *container.Container) error
 
 	// RegisterRoutes registers the module's routes
 	RegisterRoutes(e *echo.Echo, group string)
 
-	// Migrations returns the module's database migrations
-	Migrations() error
+	// Migrations returns the module's versioned database migrations
+	Migrations() []migration.Migration
 
 	// Logger returns the module's logger
 	Logger() *logger.Logger
 }
+
+// Starter is implemented by modules that run background work, such as
+// consumers or schedulers, once the HTTP server is up
+type Starter interface {
+	// Start starts the module; ctx is cancelled when the application begins shutting down
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by modules that need to release resources on shutdown
+type Stopper interface {
+	// Stop stops the module; it should return before ctx reaches its deadline
+	Stop(ctx context.Context) error
+}
+
+// Configurable is implemented by modules that own a [modules.<name>] section
+// in the configuration file
+type Configurable interface {
+	// Config returns a pointer to the struct the section is decoded into; it
+	// is decoded and validated before Initialize is called
+	Config() interface{}
+}
+
+// HealthChecker is implemented by modules that can report their own health;
+// the result is part of the readiness report
+type HealthChecker interface {
+	// HealthCheck returns an error when the module cannot serve requests
+	HealthCheck(ctx context.Context) error
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownModule is returned when the configuration enables a module that was never registered
+var ErrUnknownModule = errors.New("unknown module")
+
+// Factory creates a new instance of a module
+type Factory func() Module
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a module available under name. It is meant to be called
+// from the init function of the module package, so importing the package is
+// enough to make the module loadable. Register panics if name is registered twice.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("app: Register factory is nil for module " + name)
+	}
+	if _, exists := registry[name]; exists {
+		panic("app: Register called twice for module " + name)
+	}
+	registry[name] = factory
+}
+
+// Registered returns the names of every registered module, sorted
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Lookup returns the factory of the module registered under name
+func Lookup(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factory, exists := registry[name]
+	return factory, exists
+}
+
+// LoadModules creates and registers the modules named in enabled. When
+// enabled is empty every registered module is loaded.
+func (a *App) LoadModules(enabled []string) error {
+	if len(enabled) == 0 {
+		enabled = Registered()
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, name := range enabled {
+		factory, exists := registry[name]
+		if !exists {
+			return fmt.Errorf("%w: %s", ErrUnknownModule, name)
+		}
+		a.RegisterModule(factory())
+	}
+
+	return nil
+}
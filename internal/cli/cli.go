@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go-modular/internal/app"
+	"go-modular/internal/pkg/config"
+	"go-modular/internal/pkg/logger"
+	"go-modular/internal/pkg/scaffold"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+const usage = `Usage: %s [-c config.toml] <command> [arguments]
+
+Commands:
+  serve                          run pending migrations and start the HTTP server (default)
+  migrate up                     apply pending migrations of every enabled module
+  migrate down -module NAME [-to VERSION]
+                                 roll a module back to VERSION (0 rolls back everything)
+  migrate status                 list migrations and whether they are applied
+  routes                         list the HTTP routes without starting the server
+  config validate                check the configuration and every module section
+  modules list                   list registered modules and whether they are enabled
+  new-module [-register] NAME    generate a module skeleton under modules/NAME; -register
+                                 adds it to main.go
+
+`
+
+// command runs a subcommand against a loaded configuration
+type command func(args []string, out io.Writer) error
+
+// Run parses the command line and runs the requested command, returning the
+// process exit code
+func Run(args []string) int {
+	flags := flag.NewFlagSet("go-modular", flag.ContinueOnError)
+	configFile := flags.String("c", "config.toml", "configuration file")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), usage, flags.Name())
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	commands := map[string]command{
+		"serve":   serve,
+		"migrate": migrate,
+		"routes":  routes,
+		"config":  configCommand,
+		"modules": modules,
+	}
+
+	name, rest := "serve", flags.Args()
+	if len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+
+	// new-module only writes files and needs no configuration
+	if name == "new-module" {
+		if err := newModule(rest, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error : %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	run, exists := commands[name]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		flags.Usage()
+		return 2
+	}
+
+	// Load configuration
+	cfg := config.NewConfig(*configFile)
+	if err := cfg.Initialize(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading config : %v\n", err)
+		return 1
+	}
+
+	if err := run(rest, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error : %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// newApp creates the application with the modules enabled in the configuration
+func newApp() (*app.App, error) {
+	// initialize logger
+	logCfg := logger.DefaultConfig()
+
+	application, err := app.NewApp(&logCfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating application: %w", err)
+	}
+
+	// register the modules enabled for this node
+	if err := application.LoadModules(config.GetStringSlice("modules.enabled")); err != nil {
+		return nil, fmt.Errorf("loading modules: %w", err)
+	}
+
+	return application, nil
+}
+
+// serve initializes the application, applies pending migrations when
+// database.auto_migrate is on and runs the HTTP server until shutdown
+func serve(args []string, out io.Writer) error {
+	application, err := newApp()
+	if err != nil {
+		return err
+	}
+
+	// migrate before Initialize, which may need the event bus tables
+	if err := application.Connect(); err != nil {
+		return err
+	}
+	if config.GetBool("database.auto_migrate") {
+		if err := application.Migrate(context.Background()); err != nil {
+			return fmt.Errorf("running migrations: %w", err)
+		}
+	}
+
+	if err := application.Initialize(); err != nil {
+		return fmt.Errorf("initializing application: %w", err)
+	}
+
+	return application.Start()
+}
+
+// migrate applies, rolls back or reports module migrations
+func migrate(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("migrate requires one of: up, down, status")
+	}
+
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	module := flags.String("module", "", "module to roll back")
+	target := flags.Int64("to", 0, "version to roll back to")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	application, err := newApp()
+	if err != nil {
+		return err
+	}
+	if err := application.Connect(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		return application.Migrate(ctx)
+	case "down":
+		if *module == "" {
+			return fmt.Errorf("migrate down requires -module")
+		}
+		return application.Rollback(ctx, *module, *target)
+	case "status":
+		statuses, err := application.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "MODULE\tVERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", status.Module, status.Version, status.Name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
+
+// routes wires the application without a database and prints its routes
+func routes(args []string, out io.Writer) error {
+	application, err := newApp()
+	if err != nil {
+		return err
+	}
+	if err := application.Inspect(); err != nil {
+		return fmt.Errorf("initializing application: %w", err)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tHANDLER")
+	for _, route := range application.Routes() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", route.Method, route.Path, route.Name)
+	}
+	return w.Flush()
+}
+
+// configCommand validates the configuration file
+func configCommand(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "validate" {
+		return fmt.Errorf("config requires: validate")
+	}
+
+	application, err := newApp()
+	if err != nil {
+		return err
+	}
+	if err := application.ValidateConfig(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "configuration is valid")
+	return nil
+}
+
+// modules lists every registered module
+func modules(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "list" {
+		return fmt.Errorf("modules requires: list")
+	}
+
+	enabled := make(map[string]bool)
+	for _, name := range config.GetStringSlice("modules.enabled") {
+		enabled[name] = true
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tENABLED\tDEPENDS ON")
+	for _, name := range app.Registered() {
+		factory, _ := app.Lookup(name)
+		dependencies := strings.Join(factory().Dependencies(), ", ")
+		if dependencies == "" {
+			dependencies = "-"
+		}
+		fmt.Fprintf(w, "%s\t%t\t%s\n", name, len(enabled) == 0 || enabled[name], dependencies)
+	}
+	return w.Flush()
+}
+
+// newModule generates a module skeleton in the current repository
+func newModule(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("new-module", flag.ContinueOnError)
+	register := flags.Bool("register", false, "add the module to main.go")
+	root := flags.String("root", ".", "repository root containing go.mod")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("new-module requires a module name")
+	}
+
+	created, err := scaffold.Generate(scaffold.Options{
+		Root:     *root,
+		Name:     flags.Arg(0),
+		Register: *register,
+	})
+	for _, path := range created {
+		fmt.Fprintf(out, "created %s\n", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *register {
+		fmt.Fprintf(out, "registered %s in main.go\n", flags.Arg(0))
+	} else {
+		fmt.Fprintf(out, "add _ \"<module>/modules/%s\" to the imports in main.go to register it\n", flags.Arg(0))
+	}
+	return nil
+}
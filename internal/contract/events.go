@@ -0,0 +1,22 @@
+package contract
+
+import (
+	"go-modular/internal/pkg/bus"
+	"strconv"
+	"time"
+)
+
+// UserCreated is published whenever a user account is stored
+type UserCreated struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserCreatedTopic carries UserCreated events, keyed by user so that later
+// events about the same user are handled after it
+var UserCreatedTopic = bus.NewTopic[UserCreated]("user.created").WithKey(func(e UserCreated) string {
+	return strconv.FormatUint(uint64(e.ID), 10)
+})
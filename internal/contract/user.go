@@ -0,0 +1,36 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUserNotFound is returned by UserPort when no user matches the lookup
+var ErrUserNotFound = errors.New("user not found")
+
+// User is the user representation shared with other modules
+type User struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Password  string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetUser asks the user module for a user by ID and is answered with a
+// *User, or ErrUserNotFound
+type GetUser struct {
+	ID uint
+}
+
+// UserPort is provided by the user module to modules that need to read or
+// store users without importing it
+type UserPort interface {
+	FindByID(ctx context.Context, id uint) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	Create(ctx context.Context, user *User) error
+	Update(ctx context.Context, user *User) error
+}
@@ -1,11 +1,33 @@
 package bus
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-modular/internal/pkg/logger"
+	"hash/fnv"
+	"log"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
 
-// Event represents an event in our system
+// Errors
+var (
+	ErrHandlerTimeout = errors.New("event handler timed out")
+	ErrHandlerPanic   = errors.New("event handler panicked")
+	ErrClosed         = errors.New("event bus is shut down")
+)
+
+// Event represents an event in our system. The embedded Metadata is
+// filled in when the event is published.
 type Event struct {
 	Type    string
 	Payload interface{}
+	Metadata
 }
 
 // EventHandler is an interface for event handlers
@@ -21,69 +43,653 @@ func (f EventHandlerFunc) Handle(event Event) {
 	f(event)
 }
 
-// EventBus manages the event distribution
+// ContextHandler is an event handler that receives a context, which is
+// cancelled when the handler timeout expires, and reports failures
+type ContextHandler interface {
+	HandleEvent(ctx context.Context, event Event) error
+}
+
+// ContextHandlerFunc is a function type that implements ContextHandler
+type ContextHandlerFunc func(ctx context.Context, event Event) error
+
+// HandleEvent calls the function itself
+func (f ContextHandlerFunc) HandleEvent(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// ErrorFunc is called whenever a handler fails, times out or panics
+type ErrorFunc func(event Event, handler string, err error)
+
+// subscriber is a handler registered for an event type
+type subscriber struct {
+	id      uint64
+	pattern string
+	handler ContextHandler
+	name    string
+	retry   *RetryPolicy
+}
+
+// SubscribeOption configures a single subscription
+type SubscribeOption func(*subscriber)
+
+// WithName names the subscription, which identifies it in logs and dead
+// letters. It defaults to the name of the handler.
+func WithName(name string) SubscribeOption {
+	return func(sub *subscriber) {
+		sub.name = name
+	}
+}
+
+// WithRetry overrides the retry policy of the bus for the subscription
+func WithRetry(policy RetryPolicy) SubscribeOption {
+	return func(sub *subscriber) {
+		sub.retry = &policy
+	}
+}
+
+// legacyHandler adapts an EventHandler to ContextHandler
+type legacyHandler struct {
+	handler EventHandler
+}
+
+func (h legacyHandler) HandleEvent(ctx context.Context, event Event) error {
+	h.handler.Handle(event)
+	return nil
+}
+
+// Option configures an EventBus
+type Option func(*EventBus)
+
+// WithWorkers sets how many events are processed concurrently
+func WithWorkers(workers int) Option {
+	return func(bus *EventBus) {
+		if workers > 0 {
+			bus.workers = workers
+		}
+	}
+}
+
+// WithHandlerTimeout bounds how long a single handler may run; zero disables
+// the timeout. A handler that times out is reported and its context
+// cancelled, and for events with a key the worker still waits for it to
+// return, so that the next event of the key does not overtake it.
+func WithHandlerTimeout(timeout time.Duration) Option {
+	return func(bus *EventBus) {
+		bus.handlerTimeout = timeout
+	}
+}
+
+// WithLogger sets the logger handler failures are reported to
+func WithLogger(log *logger.Logger) Option {
+	return func(bus *EventBus) {
+		bus.logger = log
+	}
+}
+
+// WithErrorFunc sets a callback for handler failures, in addition to logging them
+func WithErrorFunc(fn ErrorFunc) Option {
+	return func(bus *EventBus) {
+		bus.onError = fn
+	}
+}
+
+// WithDefaultRetry sets the retry policy of subscriptions that do not set their own
+func WithDefaultRetry(policy RetryPolicy) Option {
+	return func(bus *EventBus) {
+		bus.retry = policy
+	}
+}
+
+// WithDeadLetterStore records events that still fail after the last retry
+func WithDeadLetterStore(store DeadLetterStore) Option {
+	return func(bus *EventBus) {
+		bus.deadLetters = store
+	}
+}
+
+// WithTransport sets how published events reach the workers; the default
+// transport keeps them in process
+func WithTransport(transport Transport) Option {
+	return func(bus *EventBus) {
+		bus.transport = transport
+	}
+}
+
+// EventBus manages the event distribution. Views returned by WithSource
+// share the subscriptions and workers of the bus they were created from.
 type EventBus struct {
+	*hub
+	// source is recorded on the events published through this view
+	source string
+}
+
+// hub is the state shared by an event bus and its views
+type hub struct {
+	// eventChannel queues events without a key for any worker
 	eventChannel chan Event
-	handlers     map[string][]EventHandler
-	mu           sync.RWMutex
-	wg           sync.WaitGroup
+	// lanes queue keyed events, one lane per worker, so that events
+	// sharing a key are handled in order by the same worker
+	lanes []chan Event
+	// subscriptions in registration order, which is also dispatch order
+	subscriptions []subscriber
+	// matches caches the subscriptions matching each published event type
+	matches map[string][]subscriber
+	types   map[string]reflect.Type
+	nextID  uint64
+	mu      sync.RWMutex
+	// wg counts received events that have not been dispatched yet
+	wg sync.WaitGroup
+
+	// sendMu is held for reading while receiving and for writing while
+	// closing the queues, so nothing is sent on a closed channel
+	sendMu    sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	// quit is closed when shutdown begins to release blocked publishers
+	quit chan struct{}
+	// stopped is closed once every worker has exited
+	stopped chan struct{}
+	// handlerCtx is passed to handlers and cancelled when shutdown times out
+	handlerCtx    context.Context
+	cancelHandler context.CancelFunc
+
+	workers        int
+	handlerTimeout time.Duration
+	logger         *logger.Logger
+	onError        ErrorFunc
+	retry          RetryPolicy
+	deadLetters    DeadLetterStore
+	transport      Transport
+	store          EventStore
+
+	overflow     OverflowPolicy
+	blockTimeout time.Duration
+	spill        *SpillFile
+	// spillDone is closed when the spill has stopped handing events back
+	spillDone chan struct{}
+	counters  counters
+
+	schedule         ScheduleStore
+	scheduleInterval time.Duration
+	// scheduleStop is closed to stop the scheduler, which closes
+	// scheduleDone when it has returned
+	scheduleStop chan struct{}
+	scheduleDone chan struct{}
+	startOnce    sync.Once
 }
 
 // NewEventBus creates a new event bus
-func NewEventBus() *EventBus {
-	bus := &EventBus{
-		eventChannel: make(chan Event, 100), // Buffer size of 100 events
-		handlers:     make(map[string][]EventHandler),
+func NewEventBus(opts ...Option) *EventBus {
+	bus := &EventBus{hub: &hub{
+		eventChannel: make(chan Event, 100), // default buffer of 100 events, see WithBuffer
+		matches:      make(map[string][]subscriber),
+		types:        make(map[string]reflect.Type),
+		workers:      1,
+		retry:        NoRetry,
+	}}
+	for _, opt := range opts {
+		opt(bus)
+	}
+	bus.quit = make(chan struct{})
+	bus.stopped = make(chan struct{})
+	bus.scheduleStop = make(chan struct{})
+	bus.handlerCtx, bus.cancelHandler = context.WithCancel(context.Background())
+	if bus.transport == nil {
+		bus.transport = NewMemoryTransport()
 	}
-	go bus.processEvents()
+
+	bus.lanes = make([]chan Event, bus.workers)
+	var workers sync.WaitGroup
+	workers.Add(bus.workers)
+	for i := 0; i < bus.workers; i++ {
+		bus.lanes[i] = make(chan Event, cap(bus.eventChannel))
+		go func(lane chan Event) {
+			defer workers.Done()
+			bus.processEvents(lane)
+		}(bus.lanes[i])
+	}
+	go func() {
+		workers.Wait()
+		close(bus.stopped)
+	}()
+	if bus.spill != nil {
+		// events spilled by a previous run are dispatched like new ones
+		bus.wg.Add(bus.spill.Pending())
+	}
+	bus.transport.Open(bus.Decode, bus.receive)
+
 	return bus
 }
 
-// Subscribe registers a handler for a specific event type
-func (bus *EventBus) Subscribe(eventType string, handler EventHandler) {
+// Start starts receiving events from a transport shared with other
+// processes, handing spilled events back and publishing scheduled events
+// that are due. Call it once the
+// subscribers are in place, so no event arrives before its handlers.
+func (bus *EventBus) Start() {
+	bus.startOnce.Do(func() {
+		bus.transport.Start()
+		if bus.spill != nil {
+			bus.spillDone = make(chan struct{})
+			go func() {
+				defer close(bus.spillDone)
+				bus.drainSpill()
+			}()
+		}
+		if bus.schedule != nil {
+			if bus.scheduleInterval <= 0 {
+				bus.scheduleInterval = time.Second
+			}
+			bus.scheduleDone = make(chan struct{})
+			go bus.runScheduler()
+		}
+	})
+}
+
+// WithSource returns a view of the bus that records source, typically a
+// module name, on the events it publishes
+func (bus *EventBus) WithSource(source string) *EventBus {
+	return &EventBus{hub: bus.hub, source: source}
+}
+
+// Source returns the source recorded on the events published through this view
+func (bus *EventBus) Source() string {
+	return bus.source
+}
+
+// Subscribe registers a handler for an event type or a pattern such as "user.*"
+func (bus *EventBus) Subscribe(eventType string, handler EventHandler) *Subscription {
+	return bus.subscribe(eventType, legacyHandler{handler: handler}, handlerName(handler))
+}
+
+// SubscribeFunc registers a function as a handler for a specific event type
+func (bus *EventBus) SubscribeFunc(eventType string, handlerFunc func(event Event)) *Subscription {
+	return bus.Subscribe(eventType, EventHandlerFunc(handlerFunc))
+}
+
+// SubscribeContext registers a context-aware handler. Failed attempts are
+// retried, then logged, passed to the ErrorFunc and dead-lettered.
+func (bus *EventBus) SubscribeContext(eventType string, handler ContextHandler, opts ...SubscribeOption) *Subscription {
+	return bus.subscribe(eventType, handler, handlerName(handler), opts...)
+}
+
+// SubscribeContextFunc registers a context-aware function as a handler
+func (bus *EventBus) SubscribeContextFunc(eventType string, handlerFunc func(ctx context.Context, event Event) error, opts ...SubscribeOption) *Subscription {
+	return bus.SubscribeContext(eventType, ContextHandlerFunc(handlerFunc), opts...)
+}
+
+func (bus *EventBus) subscribe(eventType string, handler ContextHandler, name string, opts ...SubscribeOption) *Subscription {
+	sub := subscriber{pattern: eventType, handler: handler, name: name}
+	for _, opt := range opts {
+		opt(&sub)
+	}
+
 	bus.mu.Lock()
 	defer bus.mu.Unlock()
-	bus.handlers[eventType] = append(bus.handlers[eventType], handler)
+	bus.nextID++
+	sub.id = bus.nextID
+	bus.subscriptions = append(bus.subscriptions, sub)
+	bus.matches = make(map[string][]subscriber)
+
+	return &Subscription{bus: bus, id: sub.id, pattern: sub.pattern, name: sub.name}
 }
 
-// SubscribeFunc registers a function as a handler for a specific event type
-func (bus *EventBus) SubscribeFunc(eventType string, handlerFunc func(event Event)) {
-	bus.Subscribe(eventType, EventHandlerFunc(handlerFunc))
+// unsubscribe removes the subscriber with the given id
+func (bus *EventBus) unsubscribe(id uint64) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	for i, sub := range bus.subscriptions {
+		if sub.id == id {
+			bus.subscriptions = append(bus.subscriptions[:i:i], bus.subscriptions[i+1:]...)
+			bus.matches = make(map[string][]subscriber)
+			return
+		}
+	}
+}
+
+// Publish sends an event through the transport. With the in-memory
+// transport it blocks while the buffer is full. It returns ErrClosed once
+// shutdown has begun, or the context error if ctx is done first.
+func (bus *EventBus) Publish(ctx context.Context, event Event) error {
+	select {
+	case <-bus.quit:
+		return ErrClosed
+	default:
+	}
+	bus.stamp(ctx, &event)
+	if err := bus.transport.Send(ctx, event); err != nil {
+		return err
+	}
+	return bus.record(ctx, event)
 }
 
-// Publish sends an event to the event bus
-func (bus *EventBus) Publish(event Event) {
+// Forward hands an event over so that it cannot be lost: it is sent when the
+// transport is durable and delivered to the local handlers with Deliver
+// otherwise
+func (bus *EventBus) Forward(ctx context.Context, event Event) error {
+	if bus.transport.Durable() {
+		return bus.Publish(ctx, event)
+	}
+	return bus.Deliver(ctx, event)
+}
+
+// receive queues an event received from the transport for the workers
+func (bus *EventBus) receive(ctx context.Context, event Event) error {
+	bus.sendMu.RLock()
+	defer bus.sendMu.RUnlock()
+	if bus.closed {
+		return ErrClosed
+	}
+
+	queue := bus.queueFor(event)
 	bus.wg.Add(1)
-	bus.eventChannel <- event
-}
-
-// processEvents processes events from the event channel
-func (bus *EventBus) processEvents() {
-	for event := range bus.eventChannel {
-		bus.mu.RLock()
-		handlers, exists := bus.handlers[event.Type]
-		bus.mu.RUnlock()
-
-		if exists {
-			for _, handler := range handlers {
-				// Create a closure to ensure we use the correct handler and event
-				func(handler EventHandler, event Event) {
-					defer bus.wg.Done()
-					handler.Handle(event)
-				}(handler, event)
+
+	// while events are spilled, new ones queue up behind them
+	if bus.overflow == Spill && bus.spill != nil && bus.spill.Pending() > 0 {
+		return bus.overflowed(ctx, queue, event)
+	}
+
+	select {
+	case queue <- event:
+		return nil
+	default:
+		return bus.overflowed(ctx, queue, event)
+	}
+}
+
+// queueFor returns the lane of a keyed event, or the shared queue
+func (bus *EventBus) queueFor(event Event) chan Event {
+	if event.Key != "" {
+		return bus.lanes[laneOf(event.Key, len(bus.lanes))]
+	}
+	return bus.eventChannel
+}
+
+// processEvents runs a worker that processes the events of its lane and
+// those without a key until both queues are closed
+func (bus *EventBus) processEvents(lane chan Event) {
+	shared := bus.eventChannel
+	for lane != nil || shared != nil {
+		var event Event
+		var ok bool
+		select {
+		case event, ok = <-lane:
+			if !ok {
+				lane = nil
+				continue
+			}
+		case event, ok = <-shared:
+			if !ok {
+				shared = nil
+				continue
 			}
-		} else {
-			bus.wg.Done()
 		}
+		bus.dispatch(bus.handlerCtx, event)
+		bus.wg.Done()
+	}
+}
+
+// laneOf returns the lane of the events with the given key
+func laneOf(key string, lanes int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(lanes))
+}
+
+// Deliver runs the handlers of event in the calling goroutine and returns
+// the errors of those that failed and could not be dead-lettered. Unlike
+// Publish it only returns once every handler has finished, which lets
+// callers such as the outbox relay confirm delivery.
+func (bus *EventBus) Deliver(ctx context.Context, event Event) error {
+	select {
+	case <-bus.quit:
+		return ErrClosed
+	default:
+	}
+	bus.stamp(ctx, &event)
+	err := bus.dispatch(ctx, event)
+	if recordErr := bus.record(ctx, event); recordErr != nil {
+		return errors.Join(err, recordErr)
+	}
+	return err
+}
+
+// dispatch delivers an event to each matching handler in subscription order
+func (bus *EventBus) dispatch(ctx context.Context, event Event) error {
+	// events published by the handlers are caused by this one
+	ctx = withEvent(ctx, event)
+
+	var errs []error
+	for _, sub := range bus.matching(event.Type) {
+		attempts, err := bus.attempt(ctx, sub, event)
+		if err == nil {
+			continue
+		}
+
+		bus.reportError(event, sub.name, fmt.Errorf("%w (after %d attempts)", err, attempts))
+		if bus.deadLetters != nil {
+			dlErr := bus.deadLetter(sub, event, attempts, err)
+			if dlErr == nil {
+				continue
+			}
+			err = errors.Join(err, dlErr)
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+	}
+	return errors.Join(errs...)
+}
+
+// attempt runs a handler until it succeeds or its retry policy is
+// exhausted, and returns the number of attempts made with the last error
+func (bus *EventBus) attempt(ctx context.Context, sub subscriber, event Event) (int, error) {
+	policy := bus.retry
+	if sub.retry != nil {
+		policy = *sub.retry
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = bus.invoke(ctx, sub, event); err == nil || attempt >= policy.attempts() {
+			return attempt, err
+		}
+		if sleepErr := sleep(ctx, policy.Backoff(attempt)); sleepErr != nil {
+			return attempt, err
+		}
+	}
+}
+
+// invoke runs a single handler, converting panics to errors and giving up
+// waiting once the handler timeout expires, except for keyed events
+func (bus *EventBus) invoke(ctx context.Context, sub subscriber, event Event) error {
+	if bus.handlerTimeout <= 0 {
+		return safeHandle(ctx, sub.handler, event)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, bus.handlerTimeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- safeHandle(ctx, sub.handler, event)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		// the next event of the key must not start while this handler
+		// still runs, so keyed events wait for it to return
+		if event.Key != "" {
+			cancel()
+			<-result
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s", ErrHandlerTimeout, bus.handlerTimeout)
+		}
+		return ctx.Err()
 	}
 }
 
-// Wait waits for all published events to be processed
-func (bus *EventBus) Wait() {
-	bus.wg.Wait()
+// safeHandle calls the handler and recovers from a panic
+func safeHandle(ctx context.Context, handler ContextHandler, event Event) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%w: %v\n%s", ErrHandlerPanic, recovered, debug.Stack())
+		}
+	}()
+	return handler.HandleEvent(ctx, event)
+}
+
+// reportError logs a handler failure and passes it to the ErrorFunc
+func (bus *EventBus) reportError(event Event, handler string, err error) {
+	if bus.logger != nil {
+		bus.logger.Error("Event handler failed",
+			"event", event.Type,
+			"event_id", event.ID,
+			"source", event.Source,
+			"correlation_id", event.CorrelationID,
+			"handler", handler,
+			"error", err.Error())
+	} else {
+		log.Printf("event handler %s failed for %s %s (correlation %s): %v", handler, event.Type, event.ID, event.CorrelationID, err)
+	}
+
+	if bus.onError != nil {
+		bus.onError(event, handler, err)
+	}
+}
+
+// Subscriptions returns the names of the handlers subscribed to each event
+// type or pattern
+func (bus *EventBus) Subscriptions() map[string][]string {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+
+	subscriptions := make(map[string][]string)
+	for _, sub := range bus.subscriptions {
+		subscriptions[sub.pattern] = append(subscriptions[sub.pattern], sub.name)
+	}
+
+	return subscriptions
+}
+
+// handlerName returns a readable name for a handler, such as "handler.(*UserHandler).Handle"
+func handlerName(handler interface{}) string {
+	switch f := handler.(type) {
+	case EventHandlerFunc:
+		return funcName(f)
+	case ContextHandlerFunc:
+		return funcName(f)
+	}
+	return reflect.TypeOf(handler).String()
+}
+
+func funcName(fn interface{}) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	return strings.TrimSuffix(name, "-fm")
+}
+
+// Pending returns the number of events waiting to be processed, in the
+// shared queue and every lane together
+func (bus *EventBus) Pending() int {
+	pending := len(bus.eventChannel)
+	for _, lane := range bus.lanes {
+		pending += len(lane)
+	}
+	return pending
+}
+
+// Capacity returns the size of the shared queue and every lane together
+func (bus *EventBus) Capacity() int {
+	capacity := cap(bus.eventChannel)
+	for _, lane := range bus.lanes {
+		capacity += cap(lane)
+	}
+	return capacity
 }
 
-// Close shuts down the event bus
-func (bus *EventBus) Close() {
-	close(bus.eventChannel)
+// Saturation returns how full the fullest queue is, from 0 to 1. Publishing
+// to a queue blocks or overflows once it is full even while the others are
+// empty, which the Pending and Capacity totals hide.
+func (bus *EventBus) Saturation() float64 {
+	saturation := fill(bus.eventChannel)
+	for _, lane := range bus.lanes {
+		saturation = max(saturation, fill(lane))
+	}
+	return saturation
+}
+
+func fill(queue chan Event) float64 {
+	if cap(queue) == 0 {
+		return 0
+	}
+	return float64(len(queue)) / float64(cap(queue))
+}
+
+// Wait waits until every event received so far has been dispatched, or
+// returns the context error if ctx is done first. With the in-memory
+// transport that covers every event published so far.
+func (bus *EventBus) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		bus.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown stops the scheduler and receiving events from the transport, and
+// waits for the queued and in-flight events to be dispatched. When ctx is
+// done first, the contexts of running handlers are cancelled and the
+// context error is returned.
+func (bus *EventBus) Shutdown(ctx context.Context) error {
+	var err error
+	bus.closeOnce.Do(func() {
+		// no Start after this point
+		bus.startOnce.Do(func() {})
+		close(bus.scheduleStop)
+		if bus.scheduleDone != nil {
+			select {
+			case <-bus.scheduleDone:
+			case <-ctx.Done():
+			}
+		}
+
+		// let the transport hand over what it has already taken
+		err = bus.transport.Close(ctx)
+		close(bus.quit)
+
+		// events still spilled are dispatched by the next run
+		if bus.spill != nil {
+			if bus.spillDone != nil {
+				<-bus.spillDone
+			}
+			if spillErr := bus.spill.Close(); spillErr != nil && err == nil {
+				err = spillErr
+			}
+		}
+
+		bus.sendMu.Lock()
+		bus.closed = true
+		close(bus.eventChannel)
+		for _, lane := range bus.lanes {
+			close(lane)
+		}
+		bus.sendMu.Unlock()
+	})
+
+	select {
+	case <-bus.stopped:
+		return err
+	case <-ctx.Done():
+		bus.cancelHandler()
+		return ctx.Err()
+	}
 }
@@ -1,6 +1,14 @@
 package bus
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
 
 type testHandler struct {
 	called bool
@@ -17,7 +25,7 @@ func TestEventBus(t *testing.T) {
 	bus.Subscribe("test", handler)
 
 	event := Event{Type: "test", Payload: "Hello, world!"}
-	bus.Publish(event)
+	bus.Publish(context.Background(), event)
 
 	bus.wg.Wait()
 
@@ -27,3 +35,129 @@ func TestEventBus(t *testing.T) {
 
 	t.Log("EventBus test passed")
 }
+
+func TestEventBusRecoversFromPanic(t *testing.T) {
+	var failures []error
+	bus := NewEventBus(WithErrorFunc(func(event Event, handler string, err error) {
+		failures = append(failures, err)
+	}))
+
+	handler := &testHandler{}
+	bus.SubscribeFunc("test", func(event Event) {
+		panic("boom")
+	})
+	bus.Subscribe("test", handler)
+
+	bus.Publish(context.Background(), Event{Type: "test"})
+	bus.Publish(context.Background(), Event{Type: "test"})
+	bus.Wait(context.Background())
+
+	if !handler.called {
+		t.Errorf("handler after the panicking one was not called")
+	}
+	if len(failures) != 2 || !errors.Is(failures[0], ErrHandlerPanic) {
+		t.Errorf("expected two panic errors, got %v", failures)
+	}
+}
+
+func TestEventBusReportsHandlerErrors(t *testing.T) {
+	failed := errors.New("failed")
+	var got error
+	var name string
+	bus := NewEventBus(WithErrorFunc(func(event Event, handler string, err error) {
+		got, name = err, handler
+	}))
+
+	bus.SubscribeContextFunc("test", func(ctx context.Context, event Event) error {
+		return failed
+	})
+	bus.Publish(context.Background(), Event{Type: "test"})
+	bus.Wait(context.Background())
+
+	if !errors.Is(got, failed) {
+		t.Errorf("expected %v, got %v", failed, got)
+	}
+	if !strings.Contains(name, "TestEventBusReportsHandlerErrors") {
+		t.Errorf("unexpected handler name %q", name)
+	}
+}
+
+func TestEventBusHandlerTimeout(t *testing.T) {
+	var mu sync.Mutex
+	var failures []error
+	bus := NewEventBus(
+		WithHandlerTimeout(20*time.Millisecond),
+		WithErrorFunc(func(event Event, handler string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failures = append(failures, err)
+		}),
+	)
+
+	bus.SubscribeContextFunc("slow", func(ctx context.Context, event Event) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	})
+	bus.Publish(context.Background(), Event{Type: "slow"})
+
+	start := time.Now()
+	bus.Wait(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("slow handler blocked the bus for %s", elapsed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failures) != 1 || !errors.Is(failures[0], ErrHandlerTimeout) {
+		t.Errorf("expected a timeout error, got %v", failures)
+	}
+}
+
+func TestEventBusWorkers(t *testing.T) {
+	bus := NewEventBus(WithWorkers(4))
+
+	var running, peak int32
+	release := make(chan struct{})
+	bus.SubscribeFunc("test", func(event Event) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&running, -1)
+	})
+
+	for i := 0; i < 4; i++ {
+		bus.Publish(context.Background(), Event{Type: "test"})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&peak) < 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	bus.Wait(context.Background())
+
+	if peak := atomic.LoadInt32(&peak); peak != 4 {
+		t.Errorf("expected 4 events in flight, got %d", peak)
+	}
+}
+
+func TestEventBusWaitWithMultipleHandlers(t *testing.T) {
+	bus := NewEventBus()
+
+	first, second := &testHandler{}, &testHandler{}
+	bus.Subscribe("test", first)
+	bus.Subscribe("test", second)
+	bus.Publish(context.Background(), Event{Type: "test"})
+	bus.Publish(context.Background(), Event{Type: "other"})
+	bus.Wait(context.Background())
+
+	if !first.called || !second.called {
+		t.Errorf("Wait returned before every handler ran")
+	}
+}
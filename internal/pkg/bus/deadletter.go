@@ -0,0 +1,107 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Errors
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrUnknownSubscriber  = errors.New("no such subscriber")
+)
+
+// DeadLetter is an event a subscriber still failed to handle after its
+// last retry
+type DeadLetter struct {
+	ID         uint64          `json:"id"`
+	EventType  string          `json:"event_type"`
+	Subscriber string          `json:"subscriber"`
+	Payload    json.RawMessage `json:"payload"`
+	Metadata   Metadata        `json:"metadata"`
+	Error      string          `json:"error"`
+	Attempts   int             `json:"attempts"`
+	FailedAt   time.Time       `json:"failed_at"`
+}
+
+// DeadLetterStore keeps dead letters until they are replayed or discarded
+type DeadLetterStore interface {
+	Save(ctx context.Context, letter *DeadLetter) error
+	List(ctx context.Context, offset, limit int) ([]DeadLetter, error)
+	Get(ctx context.Context, id uint64) (*DeadLetter, error)
+	Delete(ctx context.Context, id uint64) error
+}
+
+// deadLetter records a failed delivery in the dead letter store
+func (bus *EventBus) deadLetter(sub subscriber, event Event, attempts int, cause error) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("encoding %s payload: %w", event.Type, err)
+	}
+
+	return bus.deadLetters.Save(context.Background(), &DeadLetter{
+		EventType:  event.Type,
+		Subscriber: sub.name,
+		Payload:    payload,
+		Metadata:   event.Metadata,
+		Error:      cause.Error(),
+		Attempts:   attempts,
+		FailedAt:   time.Now(),
+	})
+}
+
+// Replay delivers a dead letter again to the subscriber that failed it.
+// The caller removes the letter from its store once Replay succeeds.
+func (bus *EventBus) Replay(ctx context.Context, letter *DeadLetter) error {
+	event, err := bus.Decode(letter.EventType, letter.Payload)
+	if err != nil {
+		return err
+	}
+	event.Metadata = letter.Metadata
+	return bus.DeliverTo(ctx, letter.Subscriber, event)
+}
+
+// DeliverTo runs the named subscriber of event in the calling goroutine,
+// retrying it according to its policy but without dead-lettering failures
+func (bus *EventBus) DeliverTo(ctx context.Context, subscriber string, event Event) error {
+	bus.stamp(ctx, &event)
+	ctx = withEvent(ctx, event)
+
+	found := false
+	var errs []error
+	for _, sub := range bus.matching(event.Type) {
+		if sub.name != subscriber {
+			continue
+		}
+		found = true
+		if _, err := bus.attempt(ctx, sub, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: %s on %s", ErrUnknownSubscriber, subscriber, event.Type)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Decode builds an event from a JSON payload, using the payload type bound
+// to eventType by a typed Subscribe or Publish when there is one
+func (bus *EventBus) Decode(eventType string, data []byte) (Event, error) {
+	payloadType, typed := bus.PayloadType(eventType)
+	if !typed {
+		var payload interface{}
+		err := json.Unmarshal(data, &payload)
+		return Event{Type: eventType, Payload: payload}, err
+	}
+
+	payload := reflect.New(payloadType)
+	if err := json.Unmarshal(data, payload.Interface()); err != nil {
+		return Event{}, fmt.Errorf("decoding %s payload: %w", eventType, err)
+	}
+	return Event{Type: eventType, Payload: payload.Elem().Interface()}, nil
+}
@@ -0,0 +1,138 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Errors
+var (
+	ErrNoHandler      = errors.New("no handler registered for request")
+	ErrHandlerExists  = errors.New("a handler is already registered for request")
+	ErrRequestTimeout = errors.New("request timed out")
+)
+
+// requestHandler is a registered handler with its result type
+type requestHandler struct {
+	result reflect.Type
+	handle func(ctx context.Context, request interface{}) (interface{}, error)
+}
+
+// Dispatcher routes commands and queries to the single handler registered
+// for their Go type and returns its result to the caller
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[reflect.Type]requestHandler
+	timeout  time.Duration
+}
+
+// NewDispatcher creates a dispatcher that gives every request timeout to
+// complete; zero disables the timeout
+func NewDispatcher(timeout time.Duration) *Dispatcher {
+	return &Dispatcher{
+		handlers: make(map[reflect.Type]requestHandler),
+		timeout:  timeout,
+	}
+}
+
+// Handle registers the handler answering requests of type Req with a Res.
+// Only one handler may be registered per request type.
+func Handle[Req, Res any](d *Dispatcher, handler func(ctx context.Context, request Req) (Res, error)) error {
+	requestType := typeOf[Req]()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, exists := d.handlers[requestType]; exists {
+		return fmt.Errorf("%w: %s", ErrHandlerExists, requestType)
+	}
+
+	d.handlers[requestType] = requestHandler{
+		result: typeOf[Res](),
+		handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+			return handler(ctx, request.(Req))
+		},
+	}
+	return nil
+}
+
+// Send passes request to its handler and returns the typed result. It fails
+// with ErrNoHandler when nothing handles Req, ErrTypeMismatch when the
+// handler returns another type than Res and ErrRequestTimeout when the
+// dispatcher timeout expires first.
+func Send[Res, Req any](ctx context.Context, d *Dispatcher, request Req) (Res, error) {
+	var zero Res
+	requestType := typeOf[Req]()
+
+	d.mu.RLock()
+	handler, exists := d.handlers[requestType]
+	d.mu.RUnlock()
+	if !exists {
+		return zero, fmt.Errorf("%w: %s", ErrNoHandler, requestType)
+	}
+	if handler.result != typeOf[Res]() {
+		return zero, fmt.Errorf("%w: %s is answered with %s, not %s", ErrTypeMismatch, requestType, handler.result, typeOf[Res]())
+	}
+
+	result, err := d.call(ctx, requestType, handler, request)
+	if err != nil {
+		return zero, err
+	}
+	if result == nil {
+		return zero, nil
+	}
+	return result.(Res), nil
+}
+
+// call runs the handler in its own goroutine so a handler that ignores its
+// context still cannot hold the caller past the timeout
+func (d *Dispatcher) call(ctx context.Context, requestType reflect.Type, handler requestHandler, request interface{}) (interface{}, error) {
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
+	type reply struct {
+		result interface{}
+		err    error
+	}
+	replies := make(chan reply, 1)
+	go func() {
+		var r reply
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				r.err = fmt.Errorf("%w: %v", ErrHandlerPanic, recovered)
+			}
+			replies <- r
+		}()
+		r.result, r.err = handler.handle(ctx, request)
+	}()
+
+	select {
+	case r := <-replies:
+		return r.result, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %s", ErrRequestTimeout, requestType)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Handlers returns the sorted request types that have a handler
+func (d *Dispatcher) Handlers() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	types := make([]string, 0, len(d.handlers))
+	for requestType := range d.handlers {
+		types = append(types, requestType.String())
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,88 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type getOrder struct {
+	ID int
+}
+
+type order struct {
+	ID    int
+	Total int
+}
+
+type cancelOrder struct {
+	ID int
+}
+
+func TestDispatcherSend(t *testing.T) {
+	d := NewDispatcher(time.Second)
+	if err := Handle(d, func(ctx context.Context, query getOrder) (*order, error) {
+		if query.ID != 1 {
+			return nil, errors.New("not found")
+		}
+		return &order{ID: 1, Total: 30}, nil
+	}); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	result, err := Send[*order](context.Background(), d, getOrder{ID: 1})
+	if err != nil || result.Total != 30 {
+		t.Fatalf("unexpected result %+v, %v", result, err)
+	}
+
+	if _, err := Send[*order](context.Background(), d, getOrder{ID: 2}); err == nil || err.Error() != "not found" {
+		t.Errorf("expected the handler error, got %v", err)
+	}
+}
+
+func TestDispatcherErrors(t *testing.T) {
+	d := NewDispatcher(20 * time.Millisecond)
+	ctx := context.Background()
+
+	if _, err := Send[struct{}](ctx, d, cancelOrder{ID: 1}); !errors.Is(err, ErrNoHandler) {
+		t.Errorf("expected ErrNoHandler, got %v", err)
+	}
+
+	handler := func(ctx context.Context, command cancelOrder) (struct{}, error) {
+		time.Sleep(time.Second)
+		return struct{}{}, nil
+	}
+	if err := Handle(d, handler); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if err := Handle(d, handler); !errors.Is(err, ErrHandlerExists) {
+		t.Errorf("expected ErrHandlerExists, got %v", err)
+	}
+
+	if _, err := Send[int](ctx, d, cancelOrder{ID: 1}); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("expected ErrTypeMismatch, got %v", err)
+	}
+
+	start := time.Now()
+	if _, err := Send[struct{}](ctx, d, cancelOrder{ID: 1}); !errors.Is(err, ErrRequestTimeout) {
+		t.Errorf("expected ErrRequestTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Send waited %s for a slow handler", elapsed)
+	}
+}
+
+func TestDispatcherRecoversFromPanic(t *testing.T) {
+	d := NewDispatcher(0)
+	_ = Handle(d, func(ctx context.Context, query getOrder) (*order, error) {
+		panic("boom")
+	})
+
+	if _, err := Send[*order](context.Background(), d, getOrder{}); !errors.Is(err, ErrHandlerPanic) {
+		t.Errorf("expected ErrHandlerPanic, got %v", err)
+	}
+	if handlers := d.Handlers(); len(handlers) != 1 || handlers[0] != "bus.getOrder" {
+		t.Errorf("unexpected handlers %v", handlers)
+	}
+}
@@ -0,0 +1,126 @@
+package gormbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-modular/internal/pkg/bus"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// deadLetterRecord is a row of the event_dead_letters table
+type deadLetterRecord struct {
+	ID         uint64    `gorm:"primaryKey"`
+	EventType  string    `gorm:"size:255;not null;index"`
+	Subscriber string    `gorm:"size:255;not null"`
+	Payload    string    `gorm:"type:text;not null"`
+	Metadata   string    `gorm:"type:text"`
+	Error      string    `gorm:"type:text"`
+	Attempts   int       `gorm:"not null"`
+	FailedAt   time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for deadLetterRecord
+func (*deadLetterRecord) TableName() string {
+	return "event_dead_letters"
+}
+
+// DeadLetterStore keeps dead letters in the event_dead_letters table
+type DeadLetterStore struct {
+	db *gorm.DB
+}
+
+// NewDeadLetterStore creates a new dead letter store
+func NewDeadLetterStore(db *gorm.DB) *DeadLetterStore {
+	return &DeadLetterStore{db: db}
+}
+
+// Save stores a dead letter and sets its ID
+func (s *DeadLetterStore) Save(ctx context.Context, letter *bus.DeadLetter) error {
+	metadata, err := json.Marshal(letter.Metadata)
+	if err != nil {
+		return err
+	}
+
+	record := deadLetterRecord{
+		EventType:  letter.EventType,
+		Subscriber: letter.Subscriber,
+		Payload:    string(letter.Payload),
+		Metadata:   string(metadata),
+		Error:      letter.Error,
+		Attempts:   letter.Attempts,
+		FailedAt:   letter.FailedAt,
+	}
+	if err := s.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return err
+	}
+	letter.ID = record.ID
+	return nil
+}
+
+// List returns dead letters, oldest first
+func (s *DeadLetterStore) List(ctx context.Context, offset, limit int) ([]bus.DeadLetter, error) {
+	var records []deadLetterRecord
+	err := s.db.WithContext(ctx).Order("id").Offset(offset).Limit(limit).Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]bus.DeadLetter, len(records))
+	for i, record := range records {
+		letter, err := record.toDeadLetter()
+		if err != nil {
+			return nil, err
+		}
+		letters[i] = *letter
+	}
+	return letters, nil
+}
+
+// Get returns a dead letter by ID
+func (s *DeadLetterStore) Get(ctx context.Context, id uint64) (*bus.DeadLetter, error) {
+	var record deadLetterRecord
+	err := s.db.WithContext(ctx).First(&record, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %d", bus.ErrDeadLetterNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record.toDeadLetter()
+}
+
+// Delete removes a dead letter
+func (s *DeadLetterStore) Delete(ctx context.Context, id uint64) error {
+	result := s.db.WithContext(ctx).Delete(&deadLetterRecord{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %d", bus.ErrDeadLetterNotFound, id)
+	}
+	return nil
+}
+
+func (r *deadLetterRecord) toDeadLetter() (*bus.DeadLetter, error) {
+	letter := &bus.DeadLetter{
+		ID:         r.ID,
+		EventType:  r.EventType,
+		Subscriber: r.Subscriber,
+		Payload:    []byte(r.Payload),
+		Error:      r.Error,
+		Attempts:   r.Attempts,
+		FailedAt:   r.FailedAt,
+	}
+
+	// letters stored before version 2 have no metadata
+	if r.Metadata != "" {
+		if err := json.Unmarshal([]byte(r.Metadata), &letter.Metadata); err != nil {
+			return nil, fmt.Errorf("dead letter %d: %w", r.ID, err)
+		}
+	}
+	return letter, nil
+}
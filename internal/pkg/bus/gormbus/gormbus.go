@@ -0,0 +1,83 @@
+// Package gormbus provides database-backed stores and a database transport
+// for the event bus
+package gormbus
+
+import (
+	"go-modular/internal/pkg/migration"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migrations returns the schema of the event bus stores, applied by the
+// application before any module migration
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		{
+			Version: 1,
+			Name:    "create_dead_letters_table",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&deadLetterRecordV1{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&deadLetterRecordV1{})
+			},
+		},
+		{
+			Version: 2,
+			Name:    "add_dead_letter_metadata",
+			Up: func(tx *gorm.DB) error {
+				return tx.Migrator().AddColumn(&deadLetterRecord{}, "Metadata")
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&deadLetterRecord{}, "Metadata")
+			},
+		},
+		{
+			Version: 3,
+			Name:    "create_event_stream_tables",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&streamRecord{}, &cursorRecord{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&cursorRecord{}, &streamRecord{})
+			},
+		},
+		{
+			Version: 4,
+			Name:    "create_event_schedule_table",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&scheduleRecord{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&scheduleRecord{})
+			},
+		},
+		{
+			Version: 5,
+			Name:    "create_event_store_table",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&storedEventRecord{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&storedEventRecord{})
+			},
+		},
+	}
+}
+
+// deadLetterRecordV1 is the event_dead_letters table as version 1 created it
+type deadLetterRecordV1 struct {
+	ID         uint64    `gorm:"primaryKey"`
+	EventType  string    `gorm:"size:255;not null;index"`
+	Subscriber string    `gorm:"size:255;not null"`
+	Payload    string    `gorm:"type:text;not null"`
+	Error      string    `gorm:"type:text"`
+	Attempts   int       `gorm:"not null"`
+	FailedAt   time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for deadLetterRecordV1
+func (*deadLetterRecordV1) TableName() string {
+	return "event_dead_letters"
+}
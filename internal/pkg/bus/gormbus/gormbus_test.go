@@ -0,0 +1,104 @@
+package gormbus
+
+import (
+	"context"
+	"errors"
+	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/migration"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	// every connection to :memory: is a separate database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, m := range Migrations() {
+		if err := m.Up(db); err != nil {
+			t.Fatalf("failed to migrate: %v", err)
+		}
+	}
+	return db
+}
+
+func TestDeadLetterStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewDeadLetterStore(openTestDB(t))
+
+	for _, subscriber := range []string{"mailer", "audit"} {
+		letter := &bus.DeadLetter{
+			EventType:  "user.created",
+			Subscriber: subscriber,
+			Payload:    []byte(`{"id":1}`),
+			Metadata:   bus.Metadata{ID: "event-1", CorrelationID: "request-1", Version: 2},
+			Error:      "unavailable",
+			Attempts:   3,
+			FailedAt:   time.Now(),
+		}
+		if err := store.Save(ctx, letter); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		if letter.ID == 0 {
+			t.Fatalf("Save did not set the ID")
+		}
+	}
+
+	letters, err := store.List(ctx, 0, 10)
+	if err != nil || len(letters) != 2 || letters[0].Subscriber != "mailer" {
+		t.Fatalf("unexpected list %+v, %v", letters, err)
+	}
+
+	letter, err := store.Get(ctx, letters[1].ID)
+	if err != nil || letter.Subscriber != "audit" || string(letter.Payload) != `{"id":1}` {
+		t.Fatalf("unexpected letter %+v, %v", letter, err)
+	}
+	if letter.Metadata.ID != "event-1" || letter.Metadata.CorrelationID != "request-1" || letter.Metadata.Version != 2 {
+		t.Errorf("metadata not kept: %+v", letter.Metadata)
+	}
+
+	if err := store.Delete(ctx, letter.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, letter.ID); !errors.Is(err, bus.ErrDeadLetterNotFound) {
+		t.Errorf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+	if err := store.Delete(ctx, letter.ID); !errors.Is(err, bus.ErrDeadLetterNotFound) {
+		t.Errorf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+}
+
+func TestMigrationsRollBack(t *testing.T) {
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	migrator := migration.NewMigrator(db)
+	if _, err := migrator.Up(ctx, "bus", Migrations()); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if _, err := migrator.Down(ctx, "bus", Migrations(), 1); err != nil {
+		t.Fatalf("Down to 1: %v", err)
+	}
+	if db.Migrator().HasColumn(&deadLetterRecord{}, "Metadata") {
+		t.Errorf("metadata column still exists after rolling back to 1")
+	}
+	if _, err := migrator.Down(ctx, "bus", Migrations(), 0); err != nil {
+		t.Fatalf("Down to 0: %v", err)
+	}
+	if db.Migrator().HasTable(&deadLetterRecord{}) {
+		t.Errorf("dead letter table still exists")
+	}
+}
@@ -0,0 +1,111 @@
+package gormbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-modular/internal/pkg/bus"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// scheduleRecord is a row of the event_schedule table
+type scheduleRecord struct {
+	ID          string    `gorm:"primaryKey;size:36"`
+	EventType   string    `gorm:"size:255;not null"`
+	Payload     string    `gorm:"type:text;not null"`
+	Metadata    string    `gorm:"type:text"`
+	DueAt       time.Time `gorm:"not null;index"`
+	LockedUntil *time.Time
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for scheduleRecord
+func (*scheduleRecord) TableName() string {
+	return "event_schedule"
+}
+
+// ScheduleStore keeps scheduled events in the event_schedule table
+type ScheduleStore struct {
+	db *gorm.DB
+}
+
+// NewScheduleStore creates a new schedule store
+func NewScheduleStore(db *gorm.DB) *ScheduleStore {
+	return &ScheduleStore{db: db}
+}
+
+// Save stores a scheduled event
+func (s *ScheduleStore) Save(ctx context.Context, event *bus.ScheduledEvent) error {
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Create(&scheduleRecord{
+		ID:        event.ID,
+		EventType: event.EventType,
+		Payload:   string(event.Payload),
+		Metadata:  string(metadata),
+		DueAt:     event.DueAt,
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+// Claim returns the events due by now, earliest first, that are not held by
+// another process, and holds them for lease
+func (s *ScheduleStore) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]bus.ScheduledEvent, error) {
+	db := s.db.WithContext(ctx)
+	available := "locked_until IS NULL OR locked_until < ?"
+
+	var records []scheduleRecord
+	err := db.Where("due_at <= ?", now).
+		Where(available, now).
+		Order("due_at").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// a row is claimed by whoever updates it first
+	lockedUntil := now.Add(lease)
+	events := make([]bus.ScheduledEvent, 0, len(records))
+	for _, record := range records {
+		result := db.Model(&scheduleRecord{}).
+			Where("id = ?", record.ID).
+			Where(available, now).
+			Update("locked_until", &lockedUntil)
+		if result.Error != nil {
+			return events, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		event := bus.ScheduledEvent{
+			ID:        record.ID,
+			EventType: record.EventType,
+			Payload:   []byte(record.Payload),
+			DueAt:     record.DueAt,
+		}
+		if err := json.Unmarshal([]byte(record.Metadata), &event.Metadata); err != nil {
+			return events, fmt.Errorf("scheduled event %s: %w", record.ID, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// Delete removes a scheduled event
+func (s *ScheduleStore) Delete(ctx context.Context, id string) error {
+	result := s.db.WithContext(ctx).Where("id = ?", id).Delete(&scheduleRecord{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", bus.ErrScheduleNotFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package gormbus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-modular/internal/pkg/bus"
+)
+
+func TestScheduleStoreClaims(t *testing.T) {
+	ctx := context.Background()
+	store := NewScheduleStore(openTestDB(t))
+	now := time.Now()
+
+	for id, dueAt := range map[string]time.Time{"due": now.Add(-time.Second), "later": now.Add(time.Hour)} {
+		err := store.Save(ctx, &bus.ScheduledEvent{
+			ID:        id,
+			EventType: "greeting.sent",
+			Payload:   []byte(`{"Name":"Ada"}`),
+			Metadata:  bus.Metadata{ID: id, CorrelationID: "request-1"},
+			DueAt:     dueAt,
+		})
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	claimed, err := store.Claim(ctx, now, time.Minute, 10)
+	if err != nil || len(claimed) != 1 || claimed[0].ID != "due" {
+		t.Fatalf("unexpected claim %+v, %v", claimed, err)
+	}
+	if claimed[0].Metadata.CorrelationID != "request-1" || string(claimed[0].Payload) != `{"Name":"Ada"}` {
+		t.Errorf("event not kept: %+v", claimed[0])
+	}
+
+	if claimed, _ := store.Claim(ctx, now, time.Minute, 10); len(claimed) != 0 {
+		t.Errorf("claimed event claimed again %+v", claimed)
+	}
+	if claimed, _ := store.Claim(ctx, now.Add(2*time.Minute), time.Minute, 10); len(claimed) != 1 {
+		t.Errorf("expected the expired lease to be claimed again, got %+v", claimed)
+	}
+
+	if err := store.Delete(ctx, "due"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(ctx, "due"); !errors.Is(err, bus.ErrScheduleNotFound) {
+		t.Errorf("expected ErrScheduleNotFound, got %v", err)
+	}
+}
+
+func TestPublishAfter(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	eventBus := bus.NewEventBus(bus.WithScheduleStore(NewScheduleStore(db), 10*time.Millisecond))
+	defer eventBus.Shutdown(ctx)
+
+	received := make(chan greeting, 2)
+	if _, err := bus.Subscribe(eventBus, greetingTopic, func(ctx context.Context, g greeting) error {
+		received <- g
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	eventBus.Start()
+
+	if _, err := bus.PublishAfter(ctx, eventBus, greetingTopic, greeting{Name: "Ada"}, 50*time.Millisecond); err != nil {
+		t.Fatalf("PublishAfter: %v", err)
+	}
+	cancelled, err := bus.PublishAfter(ctx, eventBus, greetingTopic, greeting{Name: "Bob"}, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("PublishAfter: %v", err)
+	}
+	if err := eventBus.CancelScheduled(ctx, cancelled); err != nil {
+		t.Fatalf("CancelScheduled: %v", err)
+	}
+
+	select {
+	case g := <-received:
+		if g.Name != "Ada" {
+			t.Errorf("unexpected event %+v", g)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("scheduled event was not published")
+	}
+
+	select {
+	case g := <-received:
+		t.Errorf("cancelled event was published: %+v", g)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	var left int64
+	db.Model(&scheduleRecord{}).Count(&left)
+	if left != 0 {
+		t.Errorf("%d scheduled events left after publishing", left)
+	}
+}
+
+func TestPublishDueDeliversBeforeDeleting(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	eventBus := bus.NewEventBus(
+		bus.WithScheduleStore(NewScheduleStore(db), time.Hour),
+		bus.WithErrorFunc(func(bus.Event, string, error) {}),
+	)
+
+	var handled []string
+	if _, err := bus.Subscribe(eventBus, greetingTopic, func(ctx context.Context, g greeting) error {
+		if g.Name == "Bob" {
+			return errors.New("mail server down")
+		}
+		handled = append(handled, g.Name)
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	for _, name := range []string{"Ada", "Bob"} {
+		if _, err := bus.PublishAt(ctx, eventBus, greetingTopic, greeting{Name: name}, time.Now().Add(-time.Second)); err != nil {
+			t.Fatalf("PublishAt: %v", err)
+		}
+	}
+
+	if _, err := eventBus.PublishDue(ctx); err == nil {
+		t.Fatalf("expected the failed delivery to be reported")
+	}
+	// the handlers ran before PublishDue returned
+	if len(handled) != 1 || handled[0] != "Ada" {
+		t.Errorf("expected Ada to be handled, got %v", handled)
+	}
+
+	var left []scheduleRecord
+	if err := db.Find(&left).Error; err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(left) != 1 || !strings.Contains(left[0].Payload, "Bob") {
+		t.Errorf("expected only the failed event to stay scheduled, got %+v", left)
+	}
+}
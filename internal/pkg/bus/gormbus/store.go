@@ -0,0 +1,105 @@
+package gormbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-modular/internal/pkg/bus"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// storedEventRecord is a row of the event_store table
+type storedEventRecord struct {
+	Position      uint64    `gorm:"primaryKey"`
+	EventID       string    `gorm:"size:36;not null;uniqueIndex"`
+	EventType     string    `gorm:"size:255;not null;index"`
+	Payload       string    `gorm:"type:text;not null"`
+	Metadata      string    `gorm:"type:text;not null"`
+	CorrelationID string    `gorm:"size:100;index"`
+	OccurredAt    time.Time `gorm:"not null;index"`
+	RecordedAt    time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for storedEventRecord
+func (*storedEventRecord) TableName() string {
+	return "event_store"
+}
+
+// EventStore records events in the event_store table
+type EventStore struct {
+	db *gorm.DB
+}
+
+// NewEventStore creates a new event store
+func NewEventStore(db *gorm.DB) *EventStore {
+	return &EventStore{db: db}
+}
+
+// Append records an event and sets its position. An event that is already
+// recorded, such as one the outbox relay delivers again, is left as it is.
+func (s *EventStore) Append(ctx context.Context, event *bus.StoredEvent) error {
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return err
+	}
+
+	record := storedEventRecord{
+		EventID:       event.Metadata.ID,
+		EventType:     event.Type,
+		Payload:       string(event.Payload),
+		Metadata:      string(metadata),
+		CorrelationID: event.Metadata.CorrelationID,
+		OccurredAt:    event.Metadata.OccurredAt,
+		RecordedAt:    event.RecordedAt,
+	}
+	err = s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "event_id"}}, DoNothing: true}).
+		Create(&record).Error
+	if err != nil {
+		return err
+	}
+	event.Position = record.Position
+	return nil
+}
+
+// Query returns the matching events ordered by position
+func (s *EventStore) Query(ctx context.Context, query bus.EventQuery) ([]bus.StoredEvent, error) {
+	db := s.db.WithContext(ctx).Where("position > ?", query.After)
+	if len(query.Types) > 0 {
+		db = db.Where("event_type IN ?", query.Types)
+	}
+	if !query.From.IsZero() {
+		db = db.Where("occurred_at >= ?", query.From)
+	}
+	if !query.To.IsZero() {
+		db = db.Where("occurred_at <= ?", query.To)
+	}
+	if query.CorrelationID != "" {
+		db = db.Where("correlation_id = ?", query.CorrelationID)
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	var records []storedEventRecord
+	if err := db.Order("position").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]bus.StoredEvent, len(records))
+	for i, record := range records {
+		events[i] = bus.StoredEvent{
+			Position:   record.Position,
+			Type:       record.EventType,
+			Payload:    []byte(record.Payload),
+			RecordedAt: record.RecordedAt,
+		}
+		if err := json.Unmarshal([]byte(record.Metadata), &events[i].Metadata); err != nil {
+			return nil, fmt.Errorf("stored event %d: %w", record.Position, err)
+		}
+	}
+	return events, nil
+}
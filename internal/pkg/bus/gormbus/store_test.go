@@ -0,0 +1,160 @@
+package gormbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-modular/internal/pkg/bus"
+)
+
+func TestEventStoreQuery(t *testing.T) {
+	ctx := context.Background()
+	store := NewEventStore(openTestDB(t))
+	start := time.Now().Add(-time.Hour)
+
+	events := []struct {
+		id, eventType, correlation string
+		at                         time.Duration
+	}{
+		{"event-1", "user.created", "request-1", 0},
+		{"event-2", "user.deleted", "request-1", time.Minute},
+		{"event-3", "user.created", "request-2", 2 * time.Minute},
+	}
+	for _, e := range events {
+		err := store.Append(ctx, &bus.StoredEvent{
+			Type:       e.eventType,
+			Payload:    []byte(`{}`),
+			Metadata:   bus.Metadata{ID: e.id, CorrelationID: e.correlation, OccurredAt: start.Add(e.at), Version: 1},
+			RecordedAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	// appending the same event again is ignored
+	if err := store.Append(ctx, &bus.StoredEvent{Type: "user.created", Payload: []byte(`{}`), Metadata: bus.Metadata{ID: "event-1"}}); err != nil {
+		t.Fatalf("Append duplicate: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query bus.EventQuery
+		want  []string
+	}{
+		{"all", bus.EventQuery{}, []string{"event-1", "event-2", "event-3"}},
+		{"type", bus.EventQuery{Types: []string{"user.created"}}, []string{"event-1", "event-3"}},
+		{"correlation", bus.EventQuery{CorrelationID: "request-1"}, []string{"event-1", "event-2"}},
+		{"time range", bus.EventQuery{From: start.Add(time.Minute), To: start.Add(90 * time.Second)}, []string{"event-2"}},
+		{"page", bus.EventQuery{After: 1, Limit: 1}, []string{"event-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := store.Query(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			ids := make([]string, len(stored))
+			for i, event := range stored {
+				ids[i] = event.Metadata.ID
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayStored(t *testing.T) {
+	ctx := context.Background()
+	eventBus := bus.NewEventBus(bus.WithEventStore(NewEventStore(openTestDB(t))))
+
+	for _, name := range []string{"Ada", "Bob", "Cy"} {
+		if err := bus.Publish(ctx, eventBus, greetingTopic, greeting{Name: name}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	if err := eventBus.Publish(ctx, bus.Event{Type: "farewell.sent"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	eventBus.Wait(ctx)
+
+	// a projection added later is backfilled from the store
+	var names []string
+	var ids []string
+	bus.Subscribe(eventBus, greetingTopic, func(ctx context.Context, g greeting) error {
+		event, _ := bus.EventFromContext(ctx)
+		names = append(names, g.Name)
+		ids = append(ids, event.ID)
+		return nil
+	}, bus.WithName("greetings.projection"))
+
+	replayed, err := eventBus.ReplayStored(ctx, "greetings.projection", bus.EventQuery{})
+	if err != nil || replayed != 3 {
+		t.Fatalf("expected 3 events replayed, got %d, %v", replayed, err)
+	}
+	if len(names) != 3 || names[0] != "Ada" || names[2] != "Cy" {
+		t.Errorf("unexpected replay %v", names)
+	}
+	if ids[0] == "" || ids[0] == ids[1] {
+		t.Errorf("original event IDs not kept: %v", ids)
+	}
+
+	if _, err := eventBus.ReplayStored(ctx, "nobody", bus.EventQuery{}); !errors.Is(err, bus.ErrUnknownSubscriber) {
+		t.Errorf("expected ErrUnknownSubscriber, got %v", err)
+	}
+	if _, err := bus.NewEventBus().ReplayStored(ctx, "greetings.projection", bus.EventQuery{}); !errors.Is(err, bus.ErrNoEventStore) {
+		t.Errorf("expected ErrNoEventStore, got %v", err)
+	}
+}
+
+// failingTransport rejects every event sent to it
+type failingTransport struct{}
+
+func (failingTransport) Open(bus.DecodeFunc, bus.ReceiveFunc)  {}
+func (failingTransport) Start()                                {}
+func (failingTransport) Send(context.Context, bus.Event) error { return bus.ErrBufferFull }
+func (failingTransport) Close(context.Context) error           { return nil }
+func (failingTransport) Durable() bool                         { return false }
+
+func TestEventStoreSkipsUnsentEvents(t *testing.T) {
+	ctx := context.Background()
+	store := NewEventStore(openTestDB(t))
+	eventBus := bus.NewEventBus(bus.WithTransport(failingTransport{}), bus.WithEventStore(store))
+
+	if err := bus.Publish(ctx, eventBus, greetingTopic, greeting{Name: "Ada"}); !errors.Is(err, bus.ErrBufferFull) {
+		t.Fatalf("expected ErrBufferFull, got %v", err)
+	}
+	if stored, err := store.Query(ctx, bus.EventQuery{}); err != nil || len(stored) != 0 {
+		t.Errorf("expected nothing recorded, got %+v, %v", stored, err)
+	}
+}
+
+func TestReplayStoredRunsHandlersOnce(t *testing.T) {
+	ctx := context.Background()
+	eventBus := bus.NewEventBus(bus.WithEventStore(NewEventStore(openTestDB(t))))
+	if err := bus.Publish(ctx, eventBus, greetingTopic, greeting{Name: "Ada"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	eventBus.Wait(ctx)
+
+	calls := 0
+	bus.Subscribe(eventBus, greetingTopic, func(ctx context.Context, g greeting) error {
+		calls++
+		return errors.New("projection broken")
+	}, bus.WithName("greetings.projection"), bus.WithRetry(bus.RetryPolicy{MaxAttempts: 3}))
+
+	replayed, err := eventBus.ReplayStored(ctx, "greetings.projection", bus.EventQuery{})
+	if err == nil || replayed != 0 {
+		t.Fatalf("expected the replay to fail, got %d, %v", replayed, err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the handler to run once, got %d", calls)
+	}
+}
@@ -0,0 +1,339 @@
+package gormbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/logger"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// streamRecord is a row of the event_stream table
+type streamRecord struct {
+	ID        uint64    `gorm:"primaryKey"`
+	EventType string    `gorm:"size:255;not null"`
+	Payload   string    `gorm:"type:text;not null"`
+	Metadata  string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for streamRecord
+func (*streamRecord) TableName() string {
+	return "event_stream"
+}
+
+// cursorRecord is the position of a consumer group in the event stream
+type cursorRecord struct {
+	GroupName string `gorm:"primaryKey;size:255"`
+	Position  uint64 `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+// TableName specifies the table name for cursorRecord
+func (*cursorRecord) TableName() string {
+	return "event_stream_cursors"
+}
+
+// Transport carries events between processes through the event_stream
+// table. Every process polls the table and a consumer group cursor decides
+// which process dispatches an event: processes sharing a group split the
+// events between them, processes in different groups each get all of them.
+//
+// The cursor only moves past the events handed to the bus, so events that
+// could not be queued are polled again. Events already queued in a process
+// that crashes before dispatching them are not seen again by its group.
+type Transport struct {
+	db        *gorm.DB
+	group     string
+	interval  time.Duration
+	batchSize int
+	settle    time.Duration
+	retention time.Duration
+	logger    *logger.Logger
+
+	decode  bus.DecodeFunc
+	receive bus.ReceiveFunc
+
+	stop      chan struct{}
+	done      chan struct{}
+	cancel    context.CancelFunc
+	startOnce sync.Once
+	closeOnce sync.Once
+}
+
+// TransportOption configures a Transport
+type TransportOption func(*Transport)
+
+// WithPollInterval sets how often the table is polled for new events
+func WithPollInterval(interval time.Duration) TransportOption {
+	return func(t *Transport) {
+		if interval > 0 {
+			t.interval = interval
+		}
+	}
+}
+
+// WithBatchSize sets how many events are polled at once
+func WithBatchSize(size int) TransportOption {
+	return func(t *Transport) {
+		if size > 0 {
+			t.batchSize = size
+		}
+	}
+}
+
+// WithSettleDelay leaves events younger than delay for the next poll, so a
+// transaction that took an ID but committed after a later one is not
+// skipped
+func WithSettleDelay(delay time.Duration) TransportOption {
+	return func(t *Transport) {
+		t.settle = delay
+	}
+}
+
+// WithRetention deletes events older than retention; zero keeps them forever
+func WithRetention(retention time.Duration) TransportOption {
+	return func(t *Transport) {
+		t.retention = retention
+	}
+}
+
+// WithLogger sets the logger polling failures are reported to
+func WithLogger(log *logger.Logger) TransportOption {
+	return func(t *Transport) {
+		t.logger = log
+	}
+}
+
+// NewTransport creates a database transport for the consumer group. A new
+// group starts at the end of the stream and only sees events sent after it
+// was created.
+func NewTransport(db *gorm.DB, group string, opts ...TransportOption) (*Transport, error) {
+	t := &Transport{
+		db:        db,
+		group:     group,
+		interval:  time.Second,
+		batchSize: 100,
+		settle:    time.Second,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var last uint64
+		if err := tx.Model(&streamRecord{}).Select("COALESCE(MAX(id), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&cursorRecord{GroupName: group, Position: last}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating cursor of group %s: %w", group, err)
+	}
+	return t, nil
+}
+
+// Open binds the transport to its bus
+func (t *Transport) Open(decode bus.DecodeFunc, receive bus.ReceiveFunc) {
+	t.decode = decode
+	t.receive = receive
+}
+
+// Start starts polling the table
+func (t *Transport) Start() {
+	t.startOnce.Do(func() {
+		var ctx context.Context
+		ctx, t.cancel = context.WithCancel(context.Background())
+		go t.run(ctx)
+	})
+}
+
+// Send stores an event in the table
+func (t *Transport) Send(ctx context.Context, event bus.Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("encoding %s payload: %w", event.Type, err)
+	}
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return err
+	}
+
+	return t.db.WithContext(ctx).Create(&streamRecord{
+		EventType: event.Type,
+		Payload:   string(payload),
+		Metadata:  string(metadata),
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+// Close stops polling once the batch being handed over is queued. When ctx
+// is done first the rest of that batch is left for the group.
+func (t *Transport) Close(ctx context.Context) error {
+	t.closeOnce.Do(func() {
+		close(t.stop)
+	})
+	if t.cancel == nil {
+		return nil
+	}
+
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		t.cancel()
+		<-t.done
+		return ctx.Err()
+	}
+}
+
+// Durable reports that sent events are stored in the database
+func (t *Transport) Durable() bool {
+	return true
+}
+
+// run polls the table until Close is called, draining it while batches
+// come back full
+func (t *Transport) run(ctx context.Context) {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			received, err := t.Poll(ctx)
+			if err != nil {
+				t.logError("Event stream poll failed", err)
+				break
+			}
+			if received < t.batchSize || t.stopping() {
+				break
+			}
+		}
+		if t.retention > 0 {
+			if err := t.prune(ctx); err != nil {
+				t.logError("Event stream prune failed", err)
+			}
+		}
+
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll hands the next batch of the group to the bus and returns how many
+// events were taken. The cursor row stays locked while the batch is queued,
+// so processes of a group never take the same events, and the cursor only
+// moves past the events the bus accepted: the rest of a batch that could
+// not be queued is polled again, and a process that fails before committing
+// leaves the whole batch to its group.
+func (t *Transport) Poll(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	taken := 0
+	var receiveErr error
+	// the cursor has to move past the queued events even when ctx is
+	// cancelled halfway through the batch
+	err := t.db.WithContext(context.WithoutCancel(ctx)).Transaction(func(tx *gorm.DB) error {
+		var cursor cursorRecord
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("group_name = ?", t.group).
+			First(&cursor).Error
+		if err != nil {
+			return err
+		}
+
+		records, err := t.batch(tx, cursor.Position)
+		if err != nil {
+			return err
+		}
+
+		position := cursor.Position
+		for _, record := range records {
+			event, err := t.decode(record.EventType, []byte(record.Payload))
+			if err == nil {
+				err = json.Unmarshal([]byte(record.Metadata), &event.Metadata)
+			}
+			if err != nil {
+				t.logError(fmt.Sprintf("Event %d dropped", record.ID), err)
+			} else if err := t.receive(ctx, event); err != nil {
+				receiveErr = fmt.Errorf("event %d not queued: %w", record.ID, err)
+				break
+			}
+			position = record.ID
+			taken++
+		}
+		if position == cursor.Position {
+			return nil
+		}
+		return tx.Model(&cursor).Update("position", position).Error
+	})
+	if err != nil {
+		return taken, err
+	}
+	return taken, receiveErr
+}
+
+// batch returns the next events after position, stopping at the first one
+// that has not settled so that the batch stays contiguous
+func (t *Transport) batch(tx *gorm.DB, position uint64) ([]streamRecord, error) {
+	var records []streamRecord
+	err := tx.Where("id > ?", position).
+		Order("id").
+		Limit(t.batchSize).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	settled := time.Now().Add(-t.settle)
+	for i, record := range records {
+		if record.CreatedAt.After(settled) {
+			return records[:i], nil
+		}
+	}
+	return records, nil
+}
+
+// prune deletes the events that are older than the retention period
+func (t *Transport) prune(ctx context.Context) error {
+	return t.db.WithContext(ctx).
+		Where("created_at < ?", time.Now().Add(-t.retention)).
+		Delete(&streamRecord{}).Error
+}
+
+func (t *Transport) stopping() bool {
+	select {
+	case <-t.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *Transport) logError(msg string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if t.logger != nil {
+		t.logger.Error(msg, "group", t.group, "error", err.Error())
+	} else {
+		log.Printf("%s (group %s): %v", msg, t.group, err)
+	}
+}
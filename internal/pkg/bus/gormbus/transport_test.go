@@ -0,0 +1,191 @@
+package gormbus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go-modular/internal/pkg/bus"
+
+	"gorm.io/gorm"
+)
+
+type greeting struct {
+	Name string
+}
+
+var greetingTopic = bus.NewTopic[greeting]("greeting.sent")
+
+func newTestBus(t *testing.T, db *gorm.DB, group string) *bus.EventBus {
+	transport, err := NewTransport(db, group, WithPollInterval(10*time.Millisecond), WithSettleDelay(0))
+	if err != nil {
+		t.Fatalf("NewTransport: %v", err)
+	}
+
+	eventBus := bus.NewEventBus(bus.WithTransport(transport))
+	eventBus.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		eventBus.Shutdown(ctx)
+	})
+	return eventBus
+}
+
+func TestTransportFansOutToGroups(t *testing.T) {
+	db := openTestDB(t)
+	replicas := []*bus.EventBus{newTestBus(t, db, "replica-1"), newTestBus(t, db, "replica-2")}
+
+	received := make(chan bus.Event, 2)
+	for _, replica := range replicas {
+		_, err := bus.Subscribe(replica, greetingTopic, func(ctx context.Context, g greeting) error {
+			event, _ := bus.EventFromContext(ctx)
+			received <- event
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Subscribe: %v", err)
+		}
+	}
+
+	publisher := replicas[0].WithSource("greeter")
+	if err := bus.Publish(context.Background(), publisher, greetingTopic, greeting{Name: "Ada"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-received:
+			if g, ok := event.Payload.(greeting); !ok || g.Name != "Ada" {
+				t.Errorf("unexpected payload %#v", event.Payload)
+			}
+			if event.Source != "greeter" {
+				t.Errorf("source not kept: %+v", event.Metadata)
+			}
+			ids = append(ids, event.ID)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("only %d of 2 replicas received the event", i)
+		}
+	}
+	if ids[0] == "" || ids[0] != ids[1] {
+		t.Errorf("replicas saw different event IDs %v", ids)
+	}
+}
+
+func TestTransportSplitsGroup(t *testing.T) {
+	db := openTestDB(t)
+	replicas := []*bus.EventBus{newTestBus(t, db, "users"), newTestBus(t, db, "users")}
+
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	var wg sync.WaitGroup
+	const events = 20
+	wg.Add(events)
+	for _, replica := range replicas {
+		bus.Subscribe(replica, greetingTopic, func(ctx context.Context, g greeting) error {
+			mu.Lock()
+			seen[g.Name]++
+			mu.Unlock()
+			wg.Done()
+			return nil
+		})
+	}
+
+	for i := 0; i < events; i++ {
+		name := string(rune('a' + i))
+		if err := bus.Publish(context.Background(), replicas[i%2], greetingTopic, greeting{Name: name}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("only %d of %d events handled", len(seen), events)
+	}
+
+	// give a duplicate delivery the chance to show up
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	for name, count := range seen {
+		if count != 1 {
+			t.Errorf("%s handled %d times", name, count)
+		}
+	}
+}
+
+func TestTransportNewGroupStartsAtEnd(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	first := newTestBus(t, db, "first")
+	if err := first.Publish(ctx, bus.Event{Type: "greeting.sent", Payload: greeting{Name: "old"}}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	transport, err := NewTransport(db, "late", WithSettleDelay(0))
+	if err != nil {
+		t.Fatalf("NewTransport: %v", err)
+	}
+	var received []bus.Event
+	transport.Open(bus.NewEventBus().Decode, func(ctx context.Context, event bus.Event) error {
+		received = append(received, event)
+		return nil
+	})
+	if _, err := transport.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(received) != 0 {
+		t.Errorf("new group received old events %+v", received)
+	}
+}
+
+func TestTransportRedeliversUnqueuedEvents(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	transport, err := NewTransport(db, "users", WithSettleDelay(0))
+	if err != nil {
+		t.Fatalf("NewTransport: %v", err)
+	}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		if err := transport.Send(ctx, bus.Event{Type: "greeting.sent", Payload: greeting{Name: name}}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	// the bus takes two events, then its buffer is full
+	var names []string
+	full := false
+	transport.Open(bus.NewEventBus().Decode, func(ctx context.Context, event bus.Event) error {
+		if full && len(names) == 2 {
+			return bus.ErrBufferFull
+		}
+		names = append(names, event.Payload.(map[string]interface{})["Name"].(string))
+		return nil
+	})
+
+	full = true
+	taken, err := transport.Poll(ctx)
+	if !errors.Is(err, bus.ErrBufferFull) || taken != 2 {
+		t.Fatalf("expected 2 events taken and ErrBufferFull, got %d, %v", taken, err)
+	}
+
+	full = false
+	if taken, err := transport.Poll(ctx); err != nil || taken != 2 {
+		t.Fatalf("expected the 2 remaining events, got %d, %v", taken, err)
+	}
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+}
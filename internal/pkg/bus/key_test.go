@@ -0,0 +1,149 @@
+package bus
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventsSharingKeyKeepPublishOrder(t *testing.T) {
+	ctx := context.Background()
+	bus := NewEventBus(WithWorkers(4))
+
+	var mu sync.Mutex
+	handled := make(map[string][]int)
+	bus.SubscribeContextFunc("user.updated", func(ctx context.Context, event Event) error {
+		time.Sleep(time.Duration(rand.IntN(500)) * time.Microsecond)
+		mu.Lock()
+		handled[event.Key] = append(handled[event.Key], event.Payload.(int))
+		mu.Unlock()
+		return nil
+	})
+
+	for i := 0; i < 100; i++ {
+		event := Event{Type: "user.updated", Payload: i}
+		event.Key = strconv.Itoa(i % 3)
+		if err := bus.Publish(ctx, event); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	if err := bus.Wait(ctx); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	for key, sequence := range handled {
+		for i := 1; i < len(sequence); i++ {
+			if sequence[i] < sequence[i-1] {
+				t.Fatalf("key %s handled out of order: %v", key, sequence)
+			}
+		}
+	}
+}
+
+func TestDifferentKeysRunInParallel(t *testing.T) {
+	ctx := context.Background()
+	bus := NewEventBus(WithWorkers(8))
+
+	release := make(chan struct{})
+	handled := make(chan string, 2)
+	bus.SubscribeContextFunc("user.updated", func(ctx context.Context, event Event) error {
+		if event.Payload == "slow" {
+			<-release
+		}
+		handled <- event.Key
+		return nil
+	})
+
+	// find two keys that map to different lanes
+	slow, fast := "user-1", ""
+	for i := 2; fast == ""; i++ {
+		key := "user-" + strconv.Itoa(i)
+		if laneOf(key, 8) != laneOf(slow, 8) {
+			fast = key
+		}
+	}
+
+	for _, event := range []Event{
+		{Type: "user.updated", Payload: "slow", Metadata: Metadata{Key: slow}},
+		{Type: "user.updated", Payload: "fast", Metadata: Metadata{Key: fast}},
+	} {
+		if err := bus.Publish(ctx, event); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	select {
+	case key := <-handled:
+		if key != fast {
+			t.Errorf("expected %s first, got %s", fast, key)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("a slow key blocked another key")
+	}
+	close(release)
+	if err := bus.Wait(ctx); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+}
+
+func TestTopicKey(t *testing.T) {
+	ctx := context.Background()
+	bus := NewEventBus()
+	topic := NewTopic[int]("counter.incremented").WithKey(func(n int) string {
+		return "counter-" + strconv.Itoa(n%2)
+	})
+
+	keys := make(chan string, 1)
+	if _, err := Subscribe(bus, topic, func(ctx context.Context, n int) error {
+		event, _ := EventFromContext(ctx)
+		keys <- event.Key
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	if err := Publish(ctx, bus, topic, 3); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if key := <-keys; key != "counter-1" {
+		t.Errorf("expected key counter-1, got %q", key)
+	}
+}
+
+func TestTimedOutHandlerHoldsBackItsKey(t *testing.T) {
+	ctx := context.Background()
+	bus := NewEventBus(WithHandlerTimeout(10*time.Millisecond), WithErrorFunc(func(Event, string, error) {}))
+
+	var mu sync.Mutex
+	var handled []int
+	bus.SubscribeContextFunc("user.updated", func(ctx context.Context, event Event) error {
+		// the first handler ignores its cancelled context
+		if event.Payload == 0 {
+			time.Sleep(50 * time.Millisecond)
+		}
+		mu.Lock()
+		handled = append(handled, event.Payload.(int))
+		mu.Unlock()
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		event := Event{Type: "user.updated", Payload: i}
+		event.Key = "user-1"
+		if err := bus.Publish(ctx, event); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	if err := bus.Wait(ctx); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 2 || handled[0] != 0 || handled[1] != 1 {
+		t.Errorf("expected [0 1], got %v", handled)
+	}
+}
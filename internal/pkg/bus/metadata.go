@@ -0,0 +1,104 @@
+package bus
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Metadata is the envelope of an event
+type Metadata struct {
+	// ID uniquely identifies the event, so handlers can drop duplicates
+	ID string `json:"id"`
+	// OccurredAt is when the event was published or recorded
+	OccurredAt time.Time `json:"occurred_at"`
+	// Source is the module that published the event
+	Source string `json:"source,omitempty"`
+	// CorrelationID ties together everything caused by one request
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// CausationID is the ID of the event whose handler published this one
+	CausationID string `json:"causation_id,omitempty"`
+	// Version is the schema version of the payload, starting at 1
+	Version int `json:"version"`
+	// Key partitions events, typically by aggregate ID. Events sharing a
+	// key are handled one at a time in publish order.
+	Key string `json:"key,omitempty"`
+}
+
+type (
+	correlationKey struct{}
+	eventKey       struct{}
+)
+
+// WithCorrelationID returns a context whose published events carry id as
+// their correlation ID
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, which is the one
+// of the event being handled or else the one set with WithCorrelationID
+func CorrelationID(ctx context.Context) string {
+	if event, ok := EventFromContext(ctx); ok {
+		return event.CorrelationID
+	}
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// EventFromContext returns the event being handled, which gives typed
+// handlers access to its metadata
+func EventFromContext(ctx context.Context) (Event, bool) {
+	event, ok := ctx.Value(eventKey{}).(Event)
+	return event, ok
+}
+
+func withEvent(ctx context.Context, event Event) context.Context {
+	return context.WithValue(ctx, eventKey{}, event)
+}
+
+// NewMetadata builds the envelope of an event published from ctx with the
+// given source and payload version
+func NewMetadata(ctx context.Context, source string, version int) Metadata {
+	metadata := Metadata{
+		ID:            uuid.NewString(),
+		OccurredAt:    time.Now(),
+		Source:        source,
+		CorrelationID: CorrelationID(ctx),
+		Version:       version,
+	}
+	if cause, ok := EventFromContext(ctx); ok {
+		metadata.CausationID = cause.ID
+	}
+	if metadata.CorrelationID == "" {
+		metadata.CorrelationID = metadata.ID
+	}
+	if metadata.Version < 1 {
+		metadata.Version = 1
+	}
+	return metadata
+}
+
+// stamp fills in the metadata an event was published without
+func (bus *EventBus) stamp(ctx context.Context, event *Event) {
+	if event.ID != "" {
+		return
+	}
+
+	metadata := NewMetadata(ctx, event.Source, event.Version)
+	if metadata.Source == "" {
+		metadata.Source = bus.source
+	}
+	if !event.OccurredAt.IsZero() {
+		metadata.OccurredAt = event.OccurredAt
+	}
+	if event.CorrelationID != "" {
+		metadata.CorrelationID = event.CorrelationID
+	}
+	if event.CausationID != "" {
+		metadata.CausationID = event.CausationID
+	}
+	metadata.Key = event.Key
+	event.Metadata = metadata
+}
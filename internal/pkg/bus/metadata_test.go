@@ -0,0 +1,68 @@
+package bus
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPublishStampsMetadata(t *testing.T) {
+	root := NewEventBus()
+	users := root.WithSource("user")
+	mail := root.WithSource("mail")
+
+	events := make(chan Event, 2)
+	root.SubscribeContextFunc("user.created", func(ctx context.Context, event Event) error {
+		events <- event
+		// events published while handling are caused by the handled one
+		return mail.Publish(ctx, Event{Type: "mail.sent"})
+	})
+	root.SubscribeContextFunc("mail.sent", func(ctx context.Context, event Event) error {
+		events <- event
+		return nil
+	})
+
+	ctx := WithCorrelationID(context.Background(), "request-1")
+	if err := users.Publish(ctx, Event{Type: "user.created"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	created, sent := <-events, <-events
+
+	if created.ID == "" || created.OccurredAt.IsZero() || created.Version != 1 {
+		t.Errorf("metadata not filled in: %+v", created.Metadata)
+	}
+	if created.Source != "user" || created.CorrelationID != "request-1" || created.CausationID != "" {
+		t.Errorf("unexpected metadata %+v", created.Metadata)
+	}
+	if sent.Source != "mail" || sent.CorrelationID != "request-1" || sent.CausationID != created.ID {
+		t.Errorf("unexpected metadata of caused event %+v", sent.Metadata)
+	}
+	if sent.ID == created.ID {
+		t.Errorf("events share ID %s", sent.ID)
+	}
+}
+
+func TestTypedHandlerReadsMetadata(t *testing.T) {
+	eventBus := NewEventBus()
+	topic := NewTopic[orderPlaced]("order.placed").WithVersion(3)
+
+	var got Event
+	if _, err := Subscribe(eventBus, topic, func(ctx context.Context, order orderPlaced) error {
+		got, _ = EventFromContext(ctx)
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	if err := Publish(context.Background(), eventBus, topic, orderPlaced{ID: 1}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	_ = eventBus.Wait(context.Background())
+
+	if got.Version != 3 {
+		t.Errorf("expected version 3, got %d", got.Version)
+	}
+	// without a correlation ID in the context the event starts a new chain
+	if got.CorrelationID != got.ID {
+		t.Errorf("expected correlation ID %q, got %q", got.ID, got.CorrelationID)
+	}
+}
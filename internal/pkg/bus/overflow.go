@@ -0,0 +1,221 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBufferFull is returned when an event waited the block timeout for
+// room in the buffer
+var ErrBufferFull = errors.New("event buffer is full")
+
+// OverflowPolicy decides what happens to an event that arrives while the
+// buffer is full
+type OverflowPolicy int
+
+const (
+	// Block waits for room, up to the block timeout when one is set
+	Block OverflowPolicy = iota
+	// DropNewest discards the arriving event
+	DropNewest
+	// DropOldest discards the oldest queued event to make room
+	DropOldest
+	// Spill writes the event to the spill file, from which it is queued
+	// again in order as room frees up. It blocks without a spill file.
+	Spill
+)
+
+// ParseOverflowPolicy parses the name of a policy: block, drop_newest,
+// drop_oldest or spill
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	switch strings.ToLower(name) {
+	case "block", "":
+		return Block, nil
+	case "drop_newest":
+		return DropNewest, nil
+	case "drop_oldest":
+		return DropOldest, nil
+	case "spill":
+		return Spill, nil
+	}
+	return Block, fmt.Errorf("unknown overflow policy %q", name)
+}
+
+// String returns the name of the policy
+func (p OverflowPolicy) String() string {
+	switch p {
+	case DropNewest:
+		return "drop_newest"
+	case DropOldest:
+		return "drop_oldest"
+	case Spill:
+		return "spill"
+	}
+	return "block"
+}
+
+// Stats counts what happened to events that arrived while the buffer was full
+type Stats struct {
+	// Blocked counts events that had to wait for room
+	Blocked uint64 `json:"blocked"`
+	// TimedOut counts events rejected with ErrBufferFull
+	TimedOut uint64 `json:"timed_out"`
+	// Dropped counts events discarded by DropNewest or DropOldest
+	Dropped uint64 `json:"dropped"`
+	// Spilled counts events written to the spill file
+	Spilled uint64 `json:"spilled"`
+}
+
+// counters is the live version of Stats
+type counters struct {
+	blocked  atomic.Uint64
+	timedOut atomic.Uint64
+	dropped  atomic.Uint64
+	spilled  atomic.Uint64
+}
+
+// WithBuffer sets how many events wait for a worker before the overflow
+// policy applies. Each worker's lane for keyed events has the same size.
+func WithBuffer(size int) Option {
+	return func(bus *EventBus) {
+		if size > 0 {
+			bus.eventChannel = make(chan Event, size)
+		}
+	}
+}
+
+// WithOverflow sets the overflow policy, Block by default
+func WithOverflow(policy OverflowPolicy) Option {
+	return func(bus *EventBus) {
+		bus.overflow = policy
+	}
+}
+
+// WithBlockTimeout bounds how long the Block policy waits before returning
+// ErrBufferFull; zero waits as long as the context allows
+func WithBlockTimeout(timeout time.Duration) Option {
+	return func(bus *EventBus) {
+		bus.blockTimeout = timeout
+	}
+}
+
+// WithSpill sets the file the Spill policy writes to. Spilled events,
+// including those left by a previous run, are handed back once the bus is
+// started.
+func WithSpill(spill *SpillFile) Option {
+	return func(bus *EventBus) {
+		bus.spill = spill
+	}
+}
+
+// Stats returns the overflow counters
+func (bus *EventBus) Stats() Stats {
+	return Stats{
+		Blocked:  bus.counters.blocked.Load(),
+		TimedOut: bus.counters.timedOut.Load(),
+		Dropped:  bus.counters.dropped.Load(),
+		Spilled:  bus.counters.spilled.Load(),
+	}
+}
+
+// overflowed applies the overflow policy to an event that found queue
+// full. It is called with sendMu held and the event counted in wg.
+func (bus *EventBus) overflowed(ctx context.Context, queue chan Event, event Event) error {
+	switch {
+	case bus.overflow == DropNewest:
+		bus.wg.Done()
+		bus.counters.dropped.Add(1)
+		return nil
+
+	case bus.overflow == DropOldest:
+		for {
+			select {
+			case queue <- event:
+				return nil
+			default:
+			}
+			select {
+			case <-queue:
+				bus.wg.Done()
+				bus.counters.dropped.Add(1)
+			default:
+			}
+		}
+
+	case bus.overflow == Spill && bus.spill != nil:
+		// the event stays counted in wg until the spill hands it back
+		if err := bus.spill.write(event); err != nil {
+			bus.wg.Done()
+			return err
+		}
+		bus.counters.spilled.Add(1)
+		return nil
+	}
+
+	bus.counters.blocked.Add(1)
+	var timeout <-chan time.Time
+	if bus.blockTimeout > 0 {
+		timer := time.NewTimer(bus.blockTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case queue <- event:
+		return nil
+	case <-ctx.Done():
+		bus.wg.Done()
+		return ctx.Err()
+	case <-bus.quit:
+		bus.wg.Done()
+		return ErrClosed
+	case <-timeout:
+		bus.wg.Done()
+		bus.counters.timedOut.Add(1)
+		return fmt.Errorf("%w after %s", ErrBufferFull, bus.blockTimeout)
+	}
+}
+
+// drainSpill queues the spilled events again, oldest first, until shutdown
+// begins. Events still in the file then are queued by the next run.
+func (bus *EventBus) drainSpill() {
+	for {
+		line, ok := bus.spill.next(bus.quit)
+		if !ok {
+			return
+		}
+		var record spillRecord
+		err := json.Unmarshal(line, &record)
+		var event Event
+		if err == nil {
+			event, err = bus.Decode(record.Type, record.Payload)
+		}
+		if err != nil {
+			// a record that cannot be read, such as one torn by a crash
+			bus.reportError(Event{Type: record.Type, Metadata: record.Metadata}, "spill", err)
+			bus.wg.Done()
+			bus.spill.done()
+			continue
+		}
+		event.Metadata = record.Metadata
+
+		bus.sendMu.RLock()
+		if bus.closed {
+			bus.sendMu.RUnlock()
+			return
+		}
+		select {
+		case bus.queueFor(event) <- event:
+			bus.sendMu.RUnlock()
+			bus.spill.done()
+		case <-bus.quit:
+			bus.sendMu.RUnlock()
+			return
+		}
+	}
+}
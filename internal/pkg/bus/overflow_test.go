@@ -0,0 +1,183 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+var countTopic = NewTopic[int]("count")
+
+// newBlockedBus returns a bus with a buffer of one whose only worker is
+// busy with event 0 until release is closed, and the payloads handled
+func newBlockedBus(t *testing.T, opts ...Option) (bus *EventBus, release chan struct{}, handled func() []int) {
+	bus = NewEventBus(append([]Option{WithBuffer(1)}, opts...)...)
+	bus.Start()
+	release = make(chan struct{})
+	started := make(chan struct{})
+
+	var mu sync.Mutex
+	var payloads []int
+	if _, err := Subscribe(bus, countTopic, func(ctx context.Context, n int) error {
+		if n == 0 {
+			close(started)
+			<-release
+		}
+		mu.Lock()
+		payloads = append(payloads, n)
+		mu.Unlock()
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	if err := Publish(context.Background(), bus, countTopic, 0); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	<-started
+
+	return bus, release, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int{}, payloads...)
+	}
+}
+
+func publishCounts(t *testing.T, bus *EventBus, from, to int) {
+	for n := from; n <= to; n++ {
+		if err := Publish(context.Background(), bus, countTopic, n); err != nil {
+			t.Fatalf("Publish %d: %v", n, err)
+		}
+	}
+}
+
+func TestDropNewest(t *testing.T) {
+	bus, release, handled := newBlockedBus(t, WithOverflow(DropNewest))
+	publishCounts(t, bus, 1, 3)
+	close(release)
+	bus.Wait(context.Background())
+
+	if got := handled(); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("expected [0 1], got %v", got)
+	}
+	if stats := bus.Stats(); stats.Dropped != 2 {
+		t.Errorf("expected 2 dropped, got %+v", stats)
+	}
+}
+
+func TestDropOldest(t *testing.T) {
+	bus, release, handled := newBlockedBus(t, WithOverflow(DropOldest))
+	publishCounts(t, bus, 1, 3)
+	close(release)
+	bus.Wait(context.Background())
+
+	if got := handled(); !reflect.DeepEqual(got, []int{0, 3}) {
+		t.Errorf("expected [0 3], got %v", got)
+	}
+	if stats := bus.Stats(); stats.Dropped != 2 {
+		t.Errorf("expected 2 dropped, got %+v", stats)
+	}
+}
+
+func TestBlockTimeout(t *testing.T) {
+	bus, release, _ := newBlockedBus(t, WithBlockTimeout(20*time.Millisecond))
+	defer close(release)
+	publishCounts(t, bus, 1, 1)
+
+	if err := Publish(context.Background(), bus, countTopic, 2); !errors.Is(err, ErrBufferFull) {
+		t.Errorf("expected ErrBufferFull, got %v", err)
+	}
+	if stats := bus.Stats(); stats.Blocked != 1 || stats.TimedOut != 1 {
+		t.Errorf("expected 1 blocked and timed out, got %+v", stats)
+	}
+}
+
+func TestSpillKeepsOrder(t *testing.T) {
+	spill, err := OpenSpillFile(filepath.Join(t.TempDir(), "spill.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenSpillFile: %v", err)
+	}
+	bus, release, handled := newBlockedBus(t, WithOverflow(Spill), WithSpill(spill))
+	publishCounts(t, bus, 1, 6)
+	close(release)
+	bus.Wait(context.Background())
+
+	if got := handled(); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4, 5, 6}) {
+		t.Errorf("expected events in order, got %v", got)
+	}
+	if stats := bus.Stats(); stats.Spilled != 5 || stats.Dropped != 0 {
+		t.Errorf("expected 5 spilled, got %+v", stats)
+	}
+	if spill.Pending() != 0 {
+		t.Errorf("%d events left in the spill file", spill.Pending())
+	}
+}
+
+func TestSpillSurvivesShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spill.jsonl")
+	spill, err := OpenSpillFile(path)
+	if err != nil {
+		t.Fatalf("OpenSpillFile: %v", err)
+	}
+	first, release, _ := newBlockedBus(t, WithOverflow(Spill), WithSpill(spill))
+	publishCounts(t, first, 1, 4)
+
+	// shut down while events 2 to 4 are still spilled
+	shutdownErr := make(chan error)
+	go func() {
+		shutdownErr <- first.Shutdown(context.Background())
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	if err := <-shutdownErr; err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	spill, err = OpenSpillFile(path)
+	if err != nil {
+		t.Fatalf("OpenSpillFile: %v", err)
+	}
+	second := NewEventBus(WithOverflow(Spill), WithSpill(spill))
+	var got []int
+	var mu sync.Mutex
+	Subscribe(second, countTopic, func(ctx context.Context, n int) error {
+		mu.Lock()
+		got = append(got, n)
+		mu.Unlock()
+		return nil
+	})
+	second.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := second.Wait(ctx); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(got, []int{2, 3, 4}) {
+		t.Errorf("expected the spilled events [2 3 4], got %v", got)
+	}
+}
+
+func TestSaturationReportsFullestQueue(t *testing.T) {
+	bus, release, _ := newBlockedBus(t, WithWorkers(1))
+	defer close(release)
+
+	event := Event{Type: "count", Payload: 1}
+	event.Key = "user-1"
+	if err := bus.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if pending, capacity := bus.Pending(), bus.Capacity(); pending != 1 || capacity != 2 {
+		t.Errorf("expected 1 of 2 pending overall, got %d of %d", pending, capacity)
+	}
+	if saturation := bus.Saturation(); saturation != 1 {
+		t.Errorf("expected the lane to be full, got %v", saturation)
+	}
+}
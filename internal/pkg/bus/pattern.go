@@ -0,0 +1,79 @@
+package bus
+
+import "strings"
+
+// Event types are dot-separated, such as "user.created". A subscription
+// pattern may use "*" to match exactly one segment and "#" to match zero or
+// more segments: "user.*" matches "user.created" but not "user.role.changed",
+// "*.deleted" matches "user.deleted", and "#" matches every event.
+
+// isPattern reports whether an event type contains wildcards
+func isPattern(eventType string) bool {
+	for _, segment := range strings.Split(eventType, ".") {
+		if segment == "*" || segment == "#" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern reports whether eventType matches pattern
+func matchPattern(pattern, eventType string) bool {
+	if pattern == eventType {
+		return true
+	}
+	if !isPattern(pattern) {
+		return false
+	}
+	return matchSegments(strings.Split(pattern, "."), strings.Split(eventType, "."))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case "#":
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		case "*":
+			if len(segments) == 0 {
+				return false
+			}
+		default:
+			if len(segments) == 0 || pattern[0] != segments[0] {
+				return false
+			}
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// matching returns the subscriptions whose pattern matches eventType in
+// the order they were registered. Results are cached per event type until
+// the next subscription.
+func (bus *EventBus) matching(eventType string) []subscriber {
+	bus.mu.RLock()
+	matches, cached := bus.matches[eventType]
+	bus.mu.RUnlock()
+	if cached {
+		return matches
+	}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	matches = make([]subscriber, 0)
+	for _, sub := range bus.subscriptions {
+		if matchPattern(sub.pattern, eventType) {
+			matches = append(matches, sub)
+		}
+	}
+	bus.matches[eventType] = matches
+	return matches
+}
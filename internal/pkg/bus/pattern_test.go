@@ -0,0 +1,109 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		eventType string
+		match     bool
+	}{
+		{"user.created", "user.created", true},
+		{"user.created", "user.deleted", false},
+		{"user.*", "user.created", true},
+		{"user.*", "user", false},
+		{"user.*", "user.role.changed", false},
+		{"*.deleted", "user.deleted", true},
+		{"*.deleted", "order.item.deleted", false},
+		{"*.*", "user.created", true},
+		{"#", "user.created", true},
+		{"#", "user", true},
+		{"user.#", "user", true},
+		{"user.#", "user.role.changed", true},
+		{"user.#", "order.created", false},
+		{"#.deleted", "order.item.deleted", true},
+		{"#.deleted", "deleted", true},
+		{"#.deleted", "order.deleted.undone", false},
+		{"user.#.changed", "user.changed", true},
+		{"user.#.changed", "user.role.email.changed", true},
+		{"user.*.changed", "user.changed", false},
+	}
+
+	for _, test := range tests {
+		if got := matchPattern(test.pattern, test.eventType); got != test.match {
+			t.Errorf("matchPattern(%q, %q) = %t, expected %t", test.pattern, test.eventType, got, test.match)
+		}
+	}
+}
+
+func TestOverlappingPatternsDispatchInSubscriptionOrder(t *testing.T) {
+	bus := NewEventBus()
+
+	var calls []string
+	record := func(name string) func(ctx context.Context, event Event) error {
+		return func(ctx context.Context, event Event) error {
+			calls = append(calls, name+":"+event.Type)
+			return nil
+		}
+	}
+
+	bus.SubscribeContextFunc("#", record("all"))
+	bus.SubscribeContextFunc("user.deleted", record("exact"))
+	bus.SubscribeContextFunc("*.deleted", record("deleted"))
+	bus.SubscribeContextFunc("user.*", record("user"))
+	bus.SubscribeContextFunc("order.#", record("order"))
+
+	ctx := context.Background()
+	for _, eventType := range []string{"user.deleted", "user.created", "order.item.deleted"} {
+		if err := bus.Deliver(ctx, Event{Type: eventType}); err != nil {
+			t.Fatalf("Deliver: %v", err)
+		}
+	}
+
+	expected := []string{
+		"all:user.deleted", "exact:user.deleted", "deleted:user.deleted", "user:user.deleted",
+		"all:user.created", "user:user.created",
+		"all:order.item.deleted", "order:order.item.deleted",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestLaterSubscriptionJoinsCachedMatches(t *testing.T) {
+	bus := NewEventBus()
+
+	calls := 0
+	count := func(ctx context.Context, event Event) error {
+		calls++
+		return nil
+	}
+
+	bus.SubscribeContextFunc("user.*", count)
+	_ = bus.Deliver(context.Background(), Event{Type: "user.created"})
+	bus.SubscribeContextFunc("#", count)
+	_ = bus.Deliver(context.Background(), Event{Type: "user.created"})
+
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestTypedTopicRejectsPatterns(t *testing.T) {
+	bus := NewEventBus()
+
+	_, err := Subscribe(bus, NewTopic[string]("user.*"), func(ctx context.Context, payload string) error {
+		return nil
+	})
+	if !errors.Is(err, ErrPatternTopic) {
+		t.Errorf("expected ErrPatternTopic, got %v", err)
+	}
+	if err := Publish(context.Background(), bus, NewTopic[string]("#"), "x"); !errors.Is(err, ErrPatternTopic) {
+		t.Errorf("expected ErrPatternTopic, got %v", err)
+	}
+}
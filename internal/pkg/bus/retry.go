@@ -0,0 +1,71 @@
+package bus
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how a failing handler is retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first; values below 1 mean a single attempt
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts; zero means no cap
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after every retry; values below 1 default to 2
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction, between 0 and 1
+	Jitter float64
+}
+
+// NoRetry makes a single attempt
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// attempts returns the number of attempts the policy allows
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Backoff returns the delay before the given retry, starting at 1
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		delay *= multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := min(p.Jitter, 1)
+		delay *= 1 - jitter + 2*jitter*rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryDeadLetters is a DeadLetterStore for tests
+type memoryDeadLetters struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+func (s *memoryDeadLetters) Save(ctx context.Context, letter *DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letter.ID = uint64(len(s.letters) + 1)
+	s.letters = append(s.letters, *letter)
+	return nil
+}
+
+func (s *memoryDeadLetters) List(ctx context.Context, offset, limit int) ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeadLetter{}, s.letters...), nil
+}
+
+func (s *memoryDeadLetters) Get(ctx context.Context, id uint64) (*DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, letter := range s.letters {
+		if letter.ID == id {
+			return &letter, nil
+		}
+	}
+	return nil, ErrDeadLetterNotFound
+}
+
+func (s *memoryDeadLetters) Delete(ctx context.Context, id uint64) error {
+	return nil
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, want := range expected {
+		if got := policy.Backoff(i + 1); got != want {
+			t.Errorf("retry %d: expected %s, got %s", i+1, want, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.Backoff(1); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("jittered backoff %s out of range", got)
+		}
+	}
+}
+
+func TestEventBusRetriesFailingHandler(t *testing.T) {
+	bus := NewEventBus(WithDefaultRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+
+	calls := 0
+	bus.SubscribeContextFunc("test", func(ctx context.Context, event Event) error {
+		calls++
+		if calls < 3 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	if err := bus.Deliver(context.Background(), Event{Type: "test"}); err != nil {
+		t.Fatalf("expected success on the third attempt, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestEventBusDeadLettersExhaustedHandler(t *testing.T) {
+	store := &memoryDeadLetters{}
+	bus := NewEventBus(
+		WithDefaultRetry(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond}),
+		WithDeadLetterStore(store),
+		WithErrorFunc(func(Event, string, error) {}),
+	)
+
+	failing := true
+	calls := 0
+	bus.SubscribeContextFunc("test", func(ctx context.Context, event Event) error {
+		calls++
+		if failing {
+			return errors.New("unavailable")
+		}
+		return nil
+	}, WithName("mailer"), WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+
+	bus.Publish(context.Background(), Event{Type: "test", Payload: map[string]string{"email": "a@example.com"}})
+	bus.Wait(context.Background())
+
+	if calls != 2 {
+		t.Errorf("expected the subscription policy of 2 attempts, got %d", calls)
+	}
+	letters, _ := store.List(context.Background(), 0, 10)
+	if len(letters) != 1 {
+		t.Fatalf("expected one dead letter, got %d", len(letters))
+	}
+	letter := letters[0]
+	if letter.Subscriber != "mailer" || letter.Attempts != 2 || string(letter.Payload) != `{"email":"a@example.com"}` {
+		t.Errorf("unexpected dead letter %+v", letter)
+	}
+
+	failing = false
+	if err := bus.Replay(context.Background(), &letter); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected replay to call the handler, got %d calls", calls)
+	}
+
+	letter.Subscriber = "unknown"
+	if err := bus.Replay(context.Background(), &letter); !errors.Is(err, ErrUnknownSubscriber) {
+		t.Errorf("expected ErrUnknownSubscriber, got %v", err)
+	}
+}
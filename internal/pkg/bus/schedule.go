@@ -0,0 +1,180 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Errors
+var (
+	ErrScheduleNotFound = errors.New("scheduled event not found")
+	ErrNoScheduleStore  = errors.New("event bus has no schedule store")
+)
+
+const (
+	// scheduleLease is how long a claimed event is held back from other
+	// processes while it is being published
+	scheduleLease = time.Minute
+	// scheduleBatch is how many due events are claimed at once
+	scheduleBatch = 100
+)
+
+// ScheduledEvent is an event waiting in a ScheduleStore until it is due
+type ScheduledEvent struct {
+	// ID is the ID of the event, used to cancel it
+	ID        string          `json:"id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Metadata  Metadata        `json:"metadata"`
+	DueAt     time.Time       `json:"due_at"`
+}
+
+// ScheduleStore keeps scheduled events until they are published or
+// cancelled
+type ScheduleStore interface {
+	Save(ctx context.Context, event *ScheduledEvent) error
+	// Claim returns at most limit events due by now and holds them back
+	// from other callers for lease, so that several processes can share
+	// the store
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]ScheduledEvent, error)
+	// Delete removes an event, returning ErrScheduleNotFound when there is
+	// none with that ID
+	Delete(ctx context.Context, id string) error
+}
+
+// WithScheduleStore keeps events published with PublishAt in store, which
+// is polled every interval once the bus is started
+func WithScheduleStore(store ScheduleStore, interval time.Duration) Option {
+	return func(bus *EventBus) {
+		bus.schedule = store
+		bus.scheduleInterval = interval
+	}
+}
+
+// PublishAt stores an event to be published at the given time and returns
+// its ID, which cancels it with CancelScheduled. The metadata is taken from
+// ctx now, apart from OccurredAt which is set when the event is published.
+func (bus *EventBus) PublishAt(ctx context.Context, event Event, at time.Time) (string, error) {
+	if bus.schedule == nil {
+		return "", ErrNoScheduleStore
+	}
+	bus.stamp(ctx, &event)
+
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return "", fmt.Errorf("encoding %s payload: %w", event.Type, err)
+	}
+	err = bus.schedule.Save(ctx, &ScheduledEvent{
+		ID:        event.ID,
+		EventType: event.Type,
+		Payload:   payload,
+		Metadata:  event.Metadata,
+		DueAt:     at,
+	})
+	if err != nil {
+		return "", err
+	}
+	return event.ID, nil
+}
+
+// PublishAfter stores an event to be published once delay has passed, see
+// PublishAt
+func (bus *EventBus) PublishAfter(ctx context.Context, event Event, delay time.Duration) (string, error) {
+	return bus.PublishAt(ctx, event, time.Now().Add(delay))
+}
+
+// CancelScheduled removes a scheduled event that has not been published yet
+func (bus *EventBus) CancelScheduled(ctx context.Context, id string) error {
+	if bus.schedule == nil {
+		return ErrNoScheduleStore
+	}
+	return bus.schedule.Delete(ctx, id)
+}
+
+// PublishAt schedules payload on topic, see EventBus.PublishAt
+func PublishAt[T any](ctx context.Context, bus *EventBus, topic Topic[T], payload T, at time.Time) (string, error) {
+	event, err := topic.event(bus, payload)
+	if err != nil {
+		return "", err
+	}
+	return bus.PublishAt(ctx, event, at)
+}
+
+// PublishAfter schedules payload on topic, see EventBus.PublishAfter
+func PublishAfter[T any](ctx context.Context, bus *EventBus, topic Topic[T], payload T, delay time.Duration) (string, error) {
+	return PublishAt(ctx, bus, topic, payload, time.Now().Add(delay))
+}
+
+// runScheduler publishes due events until shutdown begins
+func (bus *EventBus) runScheduler() {
+	defer close(bus.scheduleDone)
+
+	ticker := time.NewTicker(bus.scheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			published, err := bus.PublishDue(bus.handlerCtx)
+			if err != nil {
+				bus.reportScheduleError(err)
+				break
+			}
+			if published < scheduleBatch {
+				break
+			}
+		}
+
+		select {
+		case <-bus.scheduleStop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue publishes the scheduled events that are due and returns how
+// many were claimed. Like the outbox relay it forwards each event, so with
+// the in-memory transport its handlers have run before it leaves the
+// store. An event that cannot be delivered is tried again once its lease
+// expires.
+func (bus *EventBus) PublishDue(ctx context.Context) (int, error) {
+	scheduled, err := bus.schedule.Claim(ctx, time.Now(), scheduleLease, scheduleBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, item := range scheduled {
+		event, err := bus.Decode(item.EventType, item.Payload)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scheduled event %s: %w", item.ID, err))
+			continue
+		}
+		event.Metadata = item.Metadata
+		event.OccurredAt = time.Now()
+
+		if err := bus.Forward(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("scheduled event %s: %w", item.ID, err))
+			continue
+		}
+		if err := bus.schedule.Delete(ctx, item.ID); err != nil && !errors.Is(err, ErrScheduleNotFound) {
+			errs = append(errs, fmt.Errorf("scheduled event %s: %w", item.ID, err))
+		}
+	}
+	return len(scheduled), errors.Join(errs...)
+}
+
+func (bus *EventBus) reportScheduleError(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if bus.logger != nil {
+		bus.logger.Error("Publishing scheduled events failed", "error", err.Error())
+	} else {
+		log.Printf("publishing scheduled events failed: %v", err)
+	}
+}
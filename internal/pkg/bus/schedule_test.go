@@ -0,0 +1,18 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPublishAtNeedsScheduleStore(t *testing.T) {
+	bus := NewEventBus()
+	if _, err := bus.PublishAfter(context.Background(), Event{Type: "test"}, time.Minute); !errors.Is(err, ErrNoScheduleStore) {
+		t.Errorf("expected ErrNoScheduleStore, got %v", err)
+	}
+	if err := bus.CancelScheduled(context.Background(), "event-1"); !errors.Is(err, ErrNoScheduleStore) {
+		t.Errorf("expected ErrNoScheduleStore, got %v", err)
+	}
+}
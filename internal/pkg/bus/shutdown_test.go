@@ -0,0 +1,130 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUnsubscribe(t *testing.T) {
+	bus := NewEventBus()
+	ctx := context.Background()
+
+	var first, second int32
+	sub := bus.SubscribeFunc("test", func(event Event) { atomic.AddInt32(&first, 1) })
+	bus.SubscribeFunc("#", func(event Event) { atomic.AddInt32(&second, 1) })
+
+	_ = bus.Publish(ctx, Event{Type: "test"})
+	_ = bus.Wait(ctx)
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+
+	_ = bus.Publish(ctx, Event{Type: "test"})
+	_ = bus.Wait(ctx)
+
+	if first != 1 || second != 2 {
+		t.Errorf("expected 1 and 2 calls, got %d and %d", first, second)
+	}
+	if names := bus.Subscriptions()["test"]; len(names) != 0 {
+		t.Errorf("unsubscribed handler still listed: %v", names)
+	}
+}
+
+func TestPublishAfterShutdown(t *testing.T) {
+	bus := NewEventBus()
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if err := bus.Publish(context.Background(), Event{Type: "test"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+	if err := bus.Deliver(context.Background(), Event{Type: "test"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed from Deliver, got %v", err)
+	}
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown: %v", err)
+	}
+}
+
+func TestPublishHonoursContextWhenFull(t *testing.T) {
+	bus := NewEventBus()
+	release := make(chan struct{})
+	bus.SubscribeFunc("test", func(event Event) { <-release })
+	defer close(release)
+
+	// one event is held by the worker, the rest fill the buffer of events
+	// without a key
+	for i := 0; i < cap(bus.eventChannel)+1; i++ {
+		if err := bus.Publish(context.Background(), Event{Type: "test"}); err != nil {
+			t.Fatalf("Publish %d: %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := bus.Publish(ctx, Event{Type: "test"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestShutdownDrainsQueuedEvents(t *testing.T) {
+	bus := NewEventBus()
+
+	var handled int32
+	bus.SubscribeFunc("test", func(event Event) {
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&handled, 1)
+	})
+	for i := 0; i < 10; i++ {
+		_ = bus.Publish(context.Background(), Event{Type: "test"})
+	}
+
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if handled != 10 {
+		t.Errorf("expected 10 events handled before Shutdown returned, got %d", handled)
+	}
+}
+
+func TestShutdownDeadlineCancelsHandlers(t *testing.T) {
+	bus := NewEventBus()
+
+	cancelled := make(chan struct{})
+	bus.SubscribeContextFunc("test", func(ctx context.Context, event Event) error {
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	})
+	_ = bus.Publish(context.Background(), Event{Type: "test"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := bus.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("handler context was not cancelled")
+	}
+}
+
+func TestWaitHonoursContext(t *testing.T) {
+	bus := NewEventBus()
+	release := make(chan struct{})
+	defer close(release)
+	bus.SubscribeFunc("test", func(event Event) { <-release })
+	_ = bus.Publish(context.Background(), Event{Type: "test"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := bus.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package bus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// spillRecord is a line of the spill file
+type spillRecord struct {
+	Type     string          `json:"type"`
+	Payload  json.RawMessage `json:"payload"`
+	Metadata Metadata        `json:"metadata"`
+}
+
+// SpillFile is a first-in first-out queue of events on disk, used by the
+// Spill overflow policy. Payloads are stored as JSON, so untyped events
+// come back with the types encoding/json decodes to.
+type SpillFile struct {
+	path string
+
+	mu     sync.Mutex
+	file   *os.File
+	reader *bufio.Reader
+	// offset is where the oldest pending record starts
+	offset int64
+	// reading is the length of the record returned by next, which moves
+	// offset past it once done
+	reading int64
+	// pending counts the records written but not yet handed back
+	pending int
+	// ready is signalled when a record is written
+	ready chan struct{}
+}
+
+// OpenSpillFile opens or creates the spill file at path. Records left by a
+// previous run are handed back first.
+func OpenSpillFile(path string) (*SpillFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	spill := &SpillFile{path: path, file: file, ready: make(chan struct{}, 1)}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	spill.pending = bytes.Count(data, []byte("\n"))
+	if err := spill.rewind(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return spill, nil
+}
+
+// Pending returns the number of events waiting in the file
+func (s *SpillFile) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending
+}
+
+// write appends an event to the file
+func (s *SpillFile) write(event Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("encoding %s payload: %w", event.Type, err)
+	}
+	line, err := json.Marshal(spillRecord{Type: event.Type, Payload: payload, Metadata: event.Metadata})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	s.pending++
+
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// next waits for the oldest unread record, or returns false once quit is
+// closed. The record still counts as pending until done is called.
+func (s *SpillFile) next(quit <-chan struct{}) ([]byte, bool) {
+	for {
+		s.mu.Lock()
+		line, err := s.readLine()
+		s.mu.Unlock()
+		if err == nil {
+			return line, true
+		}
+
+		select {
+		case <-s.ready:
+		case <-quit:
+			return nil, false
+		}
+	}
+}
+
+// readLine reads the record at offset; it is called with mu held
+func (s *SpillFile) readLine() ([]byte, error) {
+	if s.pending == 0 {
+		return nil, io.EOF
+	}
+	if _, err := s.file.Seek(s.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	s.reader.Reset(s.file)
+	line, err := s.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	s.reading = int64(len(line))
+	return line, nil
+}
+
+// done marks the record returned by next as handed back, and empties the
+// file once nothing is pending
+func (s *SpillFile) done() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset += s.reading
+	s.reading = 0
+	if s.pending > 0 {
+		s.pending--
+	}
+	if s.pending == 0 {
+		s.file.Truncate(0)
+		s.rewind()
+	}
+}
+
+// rewind starts reading from the beginning of the file; it is called with
+// mu held
+func (s *SpillFile) rewind() error {
+	s.offset, s.reading = 0, 0
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if s.reader == nil {
+		s.reader = bufio.NewReader(s.file)
+	}
+	s.reader.Reset(s.file)
+	return nil
+}
+
+// Close drops the records already handed back from the file, so that the
+// next run only gets the pending ones, and closes it
+func (s *SpillFile) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.offset > 0 {
+		if _, err := s.file.Seek(s.offset, io.SeekStart); err != nil {
+			return err
+		}
+		rest, err := io.ReadAll(s.file)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(s.path+".tmp", rest, 0o644); err != nil {
+			return err
+		}
+		if err := os.Rename(s.path+".tmp", s.path); err != nil {
+			return err
+		}
+	}
+	return s.file.Close()
+}
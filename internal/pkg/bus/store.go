@@ -0,0 +1,169 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoEventStore is returned when replaying from a bus without an event store
+var ErrNoEventStore = errors.New("event bus has no event store")
+
+// replayPage is how many stored events ReplayStored loads at once
+const replayPage = 100
+
+// StoredEvent is an event recorded in an EventStore
+type StoredEvent struct {
+	// Position orders the events in the store, starting at 1
+	Position   uint64          `json:"position"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload"`
+	Metadata   Metadata        `json:"metadata"`
+	RecordedAt time.Time       `json:"recorded_at"`
+}
+
+// EventQuery selects stored events; zero fields do not filter
+type EventQuery struct {
+	// Types are exact event types
+	Types []string
+	// From and To bound OccurredAt, both inclusive
+	From time.Time
+	To   time.Time
+	// CorrelationID selects everything one request caused
+	CorrelationID string
+	// After skips the events up to this position, for paging
+	After uint64
+	// Limit caps the number of events returned
+	Limit int
+}
+
+// EventStore records every published event, in publish order
+type EventStore interface {
+	// Append records an event unless one with the same ID is recorded
+	Append(ctx context.Context, event *StoredEvent) error
+	// Query returns the matching events ordered by position
+	Query(ctx context.Context, query EventQuery) ([]StoredEvent, error)
+}
+
+// WithEventStore records every published or delivered event in store, once
+// it has been sent or delivered. When recording fails the publish returns
+// the error, so that callers such as the outbox relay try again; the store
+// ignores an event ID it already has.
+func WithEventStore(store EventStore) Option {
+	return func(bus *EventBus) {
+		bus.store = store
+	}
+}
+
+// record appends a stamped event to the event store when there is one
+func (bus *EventBus) record(ctx context.Context, event Event) error {
+	if bus.store == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("encoding %s payload: %w", event.Type, err)
+	}
+	err = bus.store.Append(ctx, &StoredEvent{
+		Type:       event.Type,
+		Payload:    payload,
+		Metadata:   event.Metadata,
+		RecordedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("recording %s: %w", event.Type, err)
+	}
+	return nil
+}
+
+// ReplayStored delivers the stored events matching query, in order, to the
+// named subscriber, skipping those it is not subscribed to. Each handler
+// runs once, without its retry policy or dead-lettering. It stops at the
+// first event the subscriber fails and returns how many were delivered.
+// query.Limit caps the number of events replayed.
+func (bus *EventBus) ReplayStored(ctx context.Context, subscriber string, query EventQuery) (int, error) {
+	if bus.store == nil {
+		return 0, ErrNoEventStore
+	}
+	if !bus.hasSubscriber(subscriber) {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownSubscriber, subscriber)
+	}
+
+	limit := query.Limit
+	replayed := 0
+	for {
+		page := query
+		page.Limit = replayPage
+		if limit > 0 && limit-replayed < replayPage {
+			page.Limit = limit - replayed
+		}
+		if page.Limit <= 0 {
+			return replayed, nil
+		}
+
+		events, err := bus.store.Query(ctx, page)
+		if err != nil {
+			return replayed, err
+		}
+		for _, stored := range events {
+			query.After = stored.Position
+			if !bus.subscribedTo(subscriber, stored.Type) {
+				continue
+			}
+
+			event, err := bus.Decode(stored.Type, stored.Payload)
+			if err != nil {
+				return replayed, fmt.Errorf("event at %d: %w", stored.Position, err)
+			}
+			event.Metadata = stored.Metadata
+			if err := bus.replayTo(ctx, subscriber, event); err != nil {
+				return replayed, fmt.Errorf("event at %d: %w", stored.Position, err)
+			}
+			replayed++
+		}
+		if len(events) < page.Limit {
+			return replayed, nil
+		}
+	}
+}
+
+// replayTo runs the handlers of the named subscriber for event once
+func (bus *EventBus) replayTo(ctx context.Context, subscriber string, event Event) error {
+	ctx = withEvent(ctx, event)
+
+	var errs []error
+	for _, sub := range bus.matching(event.Type) {
+		if sub.name != subscriber {
+			continue
+		}
+		if err := bus.invoke(ctx, sub, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// hasSubscriber reports whether a subscription has the given name
+func (bus *EventBus) hasSubscriber(name string) bool {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	for _, sub := range bus.subscriptions {
+		if sub.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribedTo reports whether the named subscriber receives eventType
+func (bus *EventBus) subscribedTo(name, eventType string) bool {
+	for _, sub := range bus.matching(eventType) {
+		if sub.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package bus
+
+import "sync"
+
+// Subscription is the handle returned when subscribing to the bus
+type Subscription struct {
+	bus     *EventBus
+	id      uint64
+	pattern string
+	name    string
+	once    sync.Once
+}
+
+// Pattern returns the event type or pattern the subscription matches
+func (s *Subscription) Pattern() string {
+	return s.pattern
+}
+
+// Name returns the name of the subscription
+func (s *Subscription) Name() string {
+	return s.name
+}
+
+// Unsubscribe removes the subscription. Events already being dispatched may
+// still reach the handler; later ones will not. It is safe to call more
+// than once.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.bus.unsubscribe(s.id)
+	})
+}
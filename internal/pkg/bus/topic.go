@@ -0,0 +1,155 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Errors
+var (
+	// ErrTypeMismatch is returned when an event name is used with a payload
+	// type other than the one it was first registered with
+	ErrTypeMismatch = errors.New("event payload type mismatch")
+	// ErrPatternTopic is returned for a typed topic whose name has wildcards,
+	// since a pattern can match events of different payload types
+	ErrPatternTopic = errors.New("typed topic names cannot contain wildcards")
+)
+
+// Topic declares an event name together with the type of its payload
+type Topic[T any] struct {
+	name    string
+	version int
+	key     func(T) string
+}
+
+// NewTopic declares a topic for events named name carrying a T
+func NewTopic[T any](name string) Topic[T] {
+	return Topic[T]{name: name, version: 1}
+}
+
+// TypeTopic declares a topic named after the Go type of its payload, such as
+// "contract.UserCreated"
+func TypeTopic[T any]() Topic[T] {
+	return Topic[T]{name: typeOf[T]().String(), version: 1}
+}
+
+// WithVersion returns the topic with the schema version recorded on its
+// events. Bump it when the payload changes in a way subscribers must notice.
+func (t Topic[T]) WithVersion(version int) Topic[T] {
+	t.version = version
+	return t
+}
+
+// WithKey returns the topic with a function deriving the partition key of
+// its events from the payload, so that events about the same aggregate are
+// handled in publish order
+func (t Topic[T]) WithKey(key func(T) string) Topic[T] {
+	t.key = key
+	return t
+}
+
+// Key returns the partition key of an event carrying payload, or "" when
+// the topic has no key function
+func (t Topic[T]) Key(payload T) string {
+	if t.key == nil {
+		return ""
+	}
+	return t.key(payload)
+}
+
+// Name returns the event name of the topic
+func (t Topic[T]) Name() string {
+	return t.name
+}
+
+// Version returns the schema version of the topic
+func (t Topic[T]) Version() int {
+	return t.version
+}
+
+// Subscribe registers a handler receiving the typed payload of every event
+// published on topic. It fails when the event name is already bound to
+// another payload type.
+func Subscribe[T any](bus *EventBus, topic Topic[T], handler func(ctx context.Context, payload T) error, opts ...SubscribeOption) (*Subscription, error) {
+	if isPattern(topic.name) {
+		return nil, fmt.Errorf("%w: %s", ErrPatternTopic, topic.name)
+	}
+	if err := bus.bindType(topic.name, typeOf[T]()); err != nil {
+		return nil, err
+	}
+	return bus.subscribe(topic.name, typedHandler[T]{fn: handler}, funcName(handler), opts...), nil
+}
+
+// Publish sends payload on topic. It fails when the event name is already
+// bound to another payload type, and otherwise like EventBus.Publish.
+func Publish[T any](ctx context.Context, bus *EventBus, topic Topic[T], payload T) error {
+	event, err := topic.event(bus, payload)
+	if err != nil {
+		return err
+	}
+	return bus.Publish(ctx, event)
+}
+
+// event builds the event carrying payload on the topic, binding the payload
+// type of its name on bus
+func (t Topic[T]) event(bus *EventBus, payload T) (Event, error) {
+	if isPattern(t.name) {
+		return Event{}, fmt.Errorf("%w: %s", ErrPatternTopic, t.name)
+	}
+	if err := bus.bindType(t.name, typeOf[T]()); err != nil {
+		return Event{}, err
+	}
+	event := Event{Type: t.name, Payload: payload}
+	event.Version = t.version
+	event.Key = t.Key(payload)
+	return event, nil
+}
+
+// typedHandler asserts the payload type before calling the handler, so an
+// untyped Publish with the wrong payload is reported instead of panicking
+type typedHandler[T any] struct {
+	fn func(ctx context.Context, payload T) error
+}
+
+func (h typedHandler[T]) HandleEvent(ctx context.Context, event Event) error {
+	var payload T
+	if event.Payload != nil {
+		typed, ok := event.Payload.(T)
+		if !ok {
+			return fmt.Errorf("%w: %s expects %s, got %T", ErrTypeMismatch, event.Type, typeOf[T](), event.Payload)
+		}
+		payload = typed
+	}
+	return h.fn(ctx, payload)
+}
+
+// bindType records the payload type of an event name, or checks it against
+// the type recorded earlier
+func (bus *EventBus) bindType(name string, payloadType reflect.Type) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	if bound, exists := bus.types[name]; exists {
+		if bound != payloadType {
+			return fmt.Errorf("%w: %s carries %s, not %s", ErrTypeMismatch, name, bound, payloadType)
+		}
+		return nil
+	}
+	bus.types[name] = payloadType
+	return nil
+}
+
+// PayloadType returns the payload type bound to an event name by a typed
+// Subscribe or Publish
+func (bus *EventBus) PayloadType(name string) (reflect.Type, bool) {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	payloadType, exists := bus.types[name]
+	return payloadType, exists
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
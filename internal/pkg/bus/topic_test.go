@@ -0,0 +1,80 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type orderPlaced struct {
+	ID int
+}
+
+func TestTypedPublishSubscribe(t *testing.T) {
+	bus := NewEventBus()
+	topic := NewTopic[orderPlaced]("order.placed")
+
+	var got orderPlaced
+	_, err := Subscribe(bus, topic, func(ctx context.Context, event orderPlaced) error {
+		got = event
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	if err := Publish(context.Background(), bus, topic, orderPlaced{ID: 42}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	bus.Wait(context.Background())
+
+	if got.ID != 42 {
+		t.Errorf("expected order 42, got %+v", got)
+	}
+}
+
+func TestTypedSubscribeRejectsOtherPayloadType(t *testing.T) {
+	bus := NewEventBus()
+	if _, err := Subscribe(bus, NewTopic[orderPlaced]("order.placed"), func(ctx context.Context, event orderPlaced) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	_, err := Subscribe(bus, NewTopic[string]("order.placed"), func(ctx context.Context, event string) error {
+		return nil
+	})
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("expected ErrTypeMismatch on subscribe, got %v", err)
+	}
+
+	err = Publish(context.Background(), bus, NewTopic[*orderPlaced]("order.placed"), &orderPlaced{})
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("expected ErrTypeMismatch on publish, got %v", err)
+	}
+}
+
+func TestTypedHandlerReportsUntypedPayload(t *testing.T) {
+	var failure error
+	bus := NewEventBus(WithErrorFunc(func(event Event, handler string, err error) {
+		failure = err
+	}))
+
+	called := false
+	if _, err := Subscribe(bus, TypeTopic[orderPlaced](), func(ctx context.Context, event orderPlaced) error {
+		called = true
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	bus.Publish(context.Background(), Event{Type: "bus.orderPlaced", Payload: "not an order"})
+	bus.Wait(context.Background())
+
+	if called {
+		t.Errorf("handler was called with the wrong payload")
+	}
+	if !errors.Is(failure, ErrTypeMismatch) {
+		t.Errorf("expected ErrTypeMismatch, got %v", failure)
+	}
+}
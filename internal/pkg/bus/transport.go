@@ -0,0 +1,59 @@
+package bus
+
+import "context"
+
+// DecodeFunc builds an event from a serialized payload, see EventBus.Decode
+type DecodeFunc func(eventType string, data []byte) (Event, error)
+
+// ReceiveFunc queues an event for the workers of the bus. It blocks while
+// the buffer is full and returns ErrClosed once shutdown has begun.
+type ReceiveFunc func(ctx context.Context, event Event) error
+
+// Transport carries published events to the buses that dispatch them. The
+// in-memory transport hands events straight to the local workers; other
+// transports can carry them between processes.
+type Transport interface {
+	// Open is called once by NewEventBus. The transport passes every event
+	// this process should handle to receive, using decode for payloads it
+	// had to serialize.
+	Open(decode DecodeFunc, receive ReceiveFunc)
+	// Start is called by EventBus.Start. A transport receiving events from
+	// other processes only takes them once started, when the subscribers
+	// are in place.
+	Start()
+	// Send publishes an event
+	Send(ctx context.Context, event Event) error
+	// Close stops receiving. It returns once receive is no longer called,
+	// or with the context error if ctx is done first.
+	Close(ctx context.Context) error
+	// Durable reports whether a sent event survives a restart of the process
+	Durable() bool
+}
+
+// memoryTransport delivers events to the bus that sent them
+type memoryTransport struct {
+	receive ReceiveFunc
+}
+
+// NewMemoryTransport creates the default in-process transport
+func NewMemoryTransport() Transport {
+	return &memoryTransport{}
+}
+
+func (t *memoryTransport) Open(decode DecodeFunc, receive ReceiveFunc) {
+	t.receive = receive
+}
+
+func (t *memoryTransport) Start() {}
+
+func (t *memoryTransport) Send(ctx context.Context, event Event) error {
+	return t.receive(ctx, event)
+}
+
+func (t *memoryTransport) Close(ctx context.Context) error {
+	return nil
+}
+
+func (t *memoryTransport) Durable() bool {
+	return false
+}
@@ -0,0 +1,67 @@
+package simplecache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Errors
+var (
+	ErrNotFound     = errors.New("cache entry not found")
+	ErrTypeMismatch = errors.New("cache entry has a different type")
+	ErrInvalidDest  = errors.New("cache destination must be a non-nil pointer")
+)
+
+// TTLs with a special meaning for Set
+const (
+	// DefaultTTL keeps the entry for the cache's default expiration
+	DefaultTTL time.Duration = 0
+	// NoExpiration keeps the entry until it is deleted or the cache cleared
+	NoExpiration time.Duration = -1
+)
+
+// Cache stores values by key. Use the typed Get and GetOrLoad helpers
+// rather than calling Get directly.
+type Cache interface {
+	// Get copies the entry stored under key into dest, a pointer, or
+	// returns ErrNotFound
+	Get(ctx context.Context, key string, dest interface{}) error
+	// Set stores value under key for ttl, see DefaultTTL and NoExpiration
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// Delete removes the entry under key, if any
+	Delete(ctx context.Context, key string) error
+	// Clear removes every entry under the cache's prefix
+	Clear(ctx context.Context) error
+	// WithPrefix returns a view of the cache storing its keys under
+	// prefix, followed by a colon, so modules sharing a cache do not
+	// overwrite or clear each other's entries
+	WithPrefix(prefix string) Cache
+}
+
+// Get returns the entry stored under key as a T
+func Get[T any](ctx context.Context, c Cache, key string) (T, error) {
+	var value T
+	if err := c.Get(ctx, key, &value); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
+
+// GetOrLoad returns the entry stored under key, or calls load and stores its
+// result for ttl when there is none. Errors from load are returned and not
+// cached.
+func GetOrLoad[T any](ctx context.Context, c Cache, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	value, err := Get[T](ctx, c, key)
+	if !errors.Is(err, ErrNotFound) {
+		return value, err
+	}
+
+	value, err = load(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, c.Set(ctx, key, value, ttl)
+}
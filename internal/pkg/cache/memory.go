@@ -0,0 +1,83 @@
+package simplecache
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// Memory is a Cache held in the process. Values are stored as they are, so
+// a value read back shares any pointers, maps or slices with the one set.
+type Memory struct {
+	cache  *cache.Cache
+	prefix string
+}
+
+// NewMemory creates an in-process cache keeping entries for defaultTTL,
+// forever when it is zero, and removing expired entries every purgeInterval
+func NewMemory(defaultTTL, purgeInterval time.Duration) *Memory {
+	if defaultTTL <= 0 {
+		defaultTTL = cache.NoExpiration
+	}
+	return &Memory{cache: cache.New(defaultTTL, purgeInterval)}
+}
+
+// Get copies the entry stored under key into dest
+func (m *Memory) Get(ctx context.Context, key string, dest interface{}) error {
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return ErrInvalidDest
+	}
+
+	data, found := m.cache.Get(m.prefix + key)
+	if !found {
+		return fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+
+	target = target.Elem()
+	value := reflect.ValueOf(data)
+	if !value.IsValid() {
+		target.SetZero()
+		return nil
+	}
+	if !value.Type().AssignableTo(target.Type()) {
+		return fmt.Errorf("%w: %s holds %s, not %s", ErrTypeMismatch, key, value.Type(), target.Type())
+	}
+	target.Set(value)
+	return nil
+}
+
+// Set stores value under key for ttl
+func (m *Memory) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	m.cache.Set(m.prefix+key, value, ttl)
+	return nil
+}
+
+// Delete removes the entry under key
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.cache.Delete(m.prefix + key)
+	return nil
+}
+
+// Clear removes every entry under the prefix
+func (m *Memory) Clear(ctx context.Context) error {
+	if m.prefix == "" {
+		m.cache.Flush()
+		return nil
+	}
+	for key := range m.cache.Items() {
+		if strings.HasPrefix(key, m.prefix) {
+			m.cache.Delete(key)
+		}
+	}
+	return nil
+}
+
+// WithPrefix returns a view of the cache storing its keys under prefix
+func (m *Memory) WithPrefix(prefix string) Cache {
+	return &Memory{cache: m.cache, prefix: m.prefix + prefix + ":"}
+}
@@ -0,0 +1,122 @@
+package simplecache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(time.Minute, time.Minute)
+
+	if _, err := Get[user](ctx, c, "user:1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := c.Set(ctx, "user:1", user{ID: 1, Name: "Ada"}, DefaultTTL); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err := Get[user](ctx, c, "user:1")
+	if err != nil || got.Name != "Ada" {
+		t.Fatalf("expected Ada, got %+v, %v", got, err)
+	}
+	if _, err := Get[string](ctx, c, "user:1"); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("expected ErrTypeMismatch, got %v", err)
+	}
+	if err := c.Get(ctx, "user:1", user{}); !errors.Is(err, ErrInvalidDest) {
+		t.Errorf("expected ErrInvalidDest, got %v", err)
+	}
+
+	if err := c.Delete(ctx, "user:1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := Get[user](ctx, c, "user:1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after Delete, got %v", err)
+	}
+
+	c.Set(ctx, "a", 1, NoExpiration)
+	c.Set(ctx, "b", 2, NoExpiration)
+	if err := c.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if _, err := Get[int](ctx, c, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after Clear, got %v", err)
+	}
+}
+
+func TestMemoryTTL(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(time.Minute, time.Minute)
+
+	c.Set(ctx, "short", "value", 10*time.Millisecond)
+	c.Set(ctx, "long", "value", DefaultTTL)
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := Get[string](ctx, c, "short"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the short entry to expire, got %v", err)
+	}
+	if _, err := Get[string](ctx, c, "long"); err != nil {
+		t.Errorf("expected the long entry to be kept, got %v", err)
+	}
+}
+
+func TestGetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(time.Minute, time.Minute)
+
+	loads := 0
+	load := func(ctx context.Context) (user, error) {
+		loads++
+		return user{ID: 1, Name: "Ada"}, nil
+	}
+	for i := 0; i < 2; i++ {
+		got, err := GetOrLoad(ctx, c, "user:1", DefaultTTL, load)
+		if err != nil || got.Name != "Ada" {
+			t.Fatalf("expected Ada, got %+v, %v", got, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected a single load, got %d", loads)
+	}
+
+	errLoad := errors.New("database down")
+	_, err := GetOrLoad(ctx, c, "user:2", DefaultTTL, func(ctx context.Context) (user, error) {
+		return user{}, errLoad
+	})
+	if !errors.Is(err, errLoad) {
+		t.Errorf("expected the load error, got %v", err)
+	}
+	if _, err := Get[user](ctx, c, "user:2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a failed load not to be cached, got %v", err)
+	}
+}
+
+func TestMemoryPrefix(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(time.Minute, time.Minute)
+	users := c.WithPrefix("users")
+	auth := c.WithPrefix("auth")
+
+	users.Set(ctx, "1", "Ada", DefaultTTL)
+	auth.Set(ctx, "1", "token", DefaultTTL)
+	if got, _ := Get[string](ctx, c, "users:1"); got != "Ada" {
+		t.Errorf("expected the key to be prefixed, got %q", got)
+	}
+
+	if err := users.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if _, err := Get[string](ctx, users, "1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected users to be cleared, got %v", err)
+	}
+	if got, err := Get[string](ctx, auth, "1"); err != nil || got != "token" {
+		t.Errorf("expected auth to be kept, got %q, %v", got, err)
+	}
+}
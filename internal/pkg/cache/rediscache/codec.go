@@ -0,0 +1,63 @@
+package rediscache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownCodec is returned by ParseCodec for an unsupported name
+var ErrUnknownCodec = errors.New("unknown cache codec")
+
+// Codec converts cache values to and from the bytes stored in Redis
+type Codec interface {
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(data []byte, dest interface{}) error
+}
+
+// JSON stores values as JSON, readable from redis-cli and other languages.
+// Only exported fields are kept.
+var JSON Codec = jsonCodec{}
+
+// Gob stores values with encoding/gob, which keeps more Go types than JSON
+// but is only readable from Go. Values held in interfaces must be
+// registered with gob.Register.
+var Gob Codec = gobCodec{}
+
+// ParseCodec returns the codec named "json" or "gob"
+func ParseCodec(name string) (Codec, error) {
+	switch name {
+	case "json":
+		return JSON, nil
+	case "gob":
+		return Gob, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCodec, name)
+	}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec) Unmarshal(data []byte, dest interface{}) error {
+	return json.Unmarshal(data, dest)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, dest interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(dest)
+}
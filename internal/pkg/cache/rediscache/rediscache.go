@@ -0,0 +1,155 @@
+package rediscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	simplecache "go-modular/internal/pkg/cache"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// clearBatch is how many keys Clear scans or deletes per command
+const clearBatch = 500
+
+// Cache is a simplecache.Cache stored in Redis, shared by every replica
+// using the same server, database and key prefix
+type Cache struct {
+	client     *redis.Client
+	codec      Codec
+	prefix     string
+	defaultTTL time.Duration
+}
+
+// Option configures a Cache
+type Option func(*Cache)
+
+// WithKeyPrefix stores every key under prefix, followed by a colon, so
+// that applications can share a Redis database
+func WithKeyPrefix(prefix string) Option {
+	return func(c *Cache) {
+		if prefix != "" {
+			c.prefix = prefix + ":"
+		}
+	}
+}
+
+// WithCodec sets how values are encoded, JSON by default
+func WithCodec(codec Codec) Option {
+	return func(c *Cache) {
+		c.codec = codec
+	}
+}
+
+// WithDefaultTTL sets how long entries set with simplecache.DefaultTTL are
+// kept; zero, the default, keeps them until they are deleted
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.defaultTTL = ttl
+	}
+}
+
+// New creates a cache on client
+func New(client *redis.Client, opts ...Option) *Cache {
+	c := &Cache{client: client, codec: JSON}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Get decodes the entry stored under key into dest
+func (c *Cache) Get(ctx context.Context, key string, dest interface{}) error {
+	if target := reflect.ValueOf(dest); target.Kind() != reflect.Pointer || target.IsNil() {
+		return simplecache.ErrInvalidDest
+	}
+
+	data, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return fmt.Errorf("%w: %s", simplecache.ErrNotFound, key)
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.codec.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("%w: %s: %v", simplecache.ErrTypeMismatch, key, err)
+	}
+	return nil
+}
+
+// Set encodes value and stores it under key for ttl
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", key, err)
+	}
+
+	switch ttl {
+	case simplecache.DefaultTTL:
+		ttl = c.defaultTTL
+	case simplecache.NoExpiration:
+		// zero, as -1 would keep the TTL of the entry being replaced
+		ttl = 0
+	}
+	return c.client.Set(ctx, c.prefix+key, data, ttl).Err()
+}
+
+// Delete removes the entry under key
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
+
+// Clear removes every key under the prefix. Without a prefix it empties the
+// whole Redis database. The keys are listed before any is deleted, as
+// deleting while scanning may skip some.
+func (c *Cache) Clear(ctx context.Context) error {
+	var keys []string
+	iter := c.client.Scan(ctx, 0, escapePattern(c.prefix)+"*", clearBatch).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	for len(keys) > 0 {
+		batch := keys[:min(clearBatch, len(keys))]
+		if err := c.client.Del(ctx, batch...).Err(); err != nil {
+			return err
+		}
+		keys = keys[len(batch):]
+	}
+	return nil
+}
+
+// WithPrefix returns a view of the cache storing its keys under prefix
+func (c *Cache) WithPrefix(prefix string) simplecache.Cache {
+	view := *c
+	view.prefix = c.prefix + prefix + ":"
+	return &view
+}
+
+// Ping checks the connection, for the readiness endpoint
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// Close closes the client and its connections
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
+
+// escapePattern escapes the glob characters SCAN MATCH would interpret
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package rediscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	simplecache "go-modular/internal/pkg/cache"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+// newTestCache returns a cache on an in-memory Redis server
+func newTestCache(t *testing.T, opts ...Option) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return New(client, opts...), server
+}
+
+func TestCache(t *testing.T) {
+	for _, codec := range []string{"json", "gob"} {
+		t.Run(codec, func(t *testing.T) {
+			ctx := context.Background()
+			parsed, err := ParseCodec(codec)
+			if err != nil {
+				t.Fatalf("ParseCodec: %v", err)
+			}
+			c, server := newTestCache(t, WithCodec(parsed), WithKeyPrefix("app"))
+
+			if _, err := simplecache.Get[user](ctx, c, "user:1"); !errors.Is(err, simplecache.ErrNotFound) {
+				t.Fatalf("expected ErrNotFound, got %v", err)
+			}
+
+			if err := c.Set(ctx, "user:1", user{ID: 1, Name: "Ada"}, simplecache.DefaultTTL); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			if !server.Exists("app:user:1") {
+				t.Errorf("expected the key to be prefixed, got %v", server.Keys())
+			}
+			got, err := simplecache.Get[user](ctx, c, "user:1")
+			if err != nil || got != (user{ID: 1, Name: "Ada"}) {
+				t.Fatalf("expected Ada, got %+v, %v", got, err)
+			}
+			if _, err := simplecache.Get[string](ctx, c, "user:1"); !errors.Is(err, simplecache.ErrTypeMismatch) {
+				t.Errorf("expected ErrTypeMismatch, got %v", err)
+			}
+
+			if err := c.Delete(ctx, "user:1"); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, err := simplecache.Get[user](ctx, c, "user:1"); !errors.Is(err, simplecache.ErrNotFound) {
+				t.Errorf("expected ErrNotFound after Delete, got %v", err)
+			}
+		})
+	}
+
+	if _, err := ParseCodec("xml"); !errors.Is(err, ErrUnknownCodec) {
+		t.Errorf("expected ErrUnknownCodec, got %v", err)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestCache(t, WithDefaultTTL(time.Hour))
+
+	c.Set(ctx, "short", "value", time.Minute)
+	c.Set(ctx, "default", "value", simplecache.DefaultTTL)
+	c.Set(ctx, "forever", "value", simplecache.NoExpiration)
+
+	if ttl := server.TTL("default"); ttl != time.Hour {
+		t.Errorf("expected the default TTL, got %s", ttl)
+	}
+	if ttl := server.TTL("forever"); ttl != 0 {
+		t.Errorf("expected no TTL, got %s", ttl)
+	}
+
+	server.FastForward(2 * time.Minute)
+	if _, err := simplecache.Get[string](ctx, c, "short"); !errors.Is(err, simplecache.ErrNotFound) {
+		t.Errorf("expected the short entry to expire, got %v", err)
+	}
+	if _, err := simplecache.Get[string](ctx, c, "default"); err != nil {
+		t.Errorf("expected the default entry to be kept, got %v", err)
+	}
+}
+
+func TestCachePrefix(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestCache(t, WithKeyPrefix("app*"))
+	users := c.WithPrefix("users")
+	auth := c.WithPrefix("auth")
+
+	for i := 0; i < clearBatch+10; i++ {
+		users.Set(ctx, fmt.Sprint(i), i, simplecache.DefaultTTL)
+	}
+	auth.Set(ctx, "1", "token", simplecache.DefaultTTL)
+	server.Set("apple", "kept")
+
+	if err := users.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if keys := server.Keys(); len(keys) != 2 {
+		t.Errorf("expected only the auth entry and apple to be kept, got %v", keys)
+	}
+	if got, err := simplecache.Get[string](ctx, auth, "1"); err != nil || got != "token" {
+		t.Errorf("expected auth to be kept, got %q, %v", got, err)
+	}
+}
+
+func TestGetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestCache(t)
+
+	loads := 0
+	for i := 0; i < 2; i++ {
+		got, err := simplecache.GetOrLoad(ctx, c, "user:1", time.Minute, func(ctx context.Context) (user, error) {
+			loads++
+			return user{ID: 1, Name: "Ada"}, nil
+		})
+		if err != nil || got.Name != "Ada" {
+			t.Fatalf("expected Ada, got %+v, %v", got, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected a single load, got %d", loads)
+	}
+}
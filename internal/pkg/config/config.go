@@ -1,7 +1,6 @@
 package config
 
 import (
-	"go-modular/internal/pkg/jwt"
 	"log"
 	"os"
 	"path/filepath"
@@ -29,6 +28,7 @@ func (c *Config) Initialize() error {
 	viper.AddConfigPath(filepath.Dir(c.filename))
 
 	viper.AutomaticEnv()
+	setDefaults()
 	err := viper.ReadInConfig()
 
 	if err != nil {
@@ -41,6 +41,49 @@ func (c *Config) Initialize() error {
 	return nil
 }
 
+// setDefaults registers fallbacks for optional keys so older config files keep working
+func setDefaults() {
+	viper.SetDefault("server.shutdown_timeout", 30)
+	viper.SetDefault("server.health_timeout", 5)
+	viper.SetDefault("server.cache_expired", 24)
+	viper.SetDefault("server.cache_purged", 60)
+	viper.SetDefault("cache.driver", "memory")
+	viper.SetDefault("cache.redis.addr", "localhost:6379")
+	viper.SetDefault("cache.redis.username", "")
+	viper.SetDefault("cache.redis.password", "")
+	viper.SetDefault("cache.redis.db", 0)
+	viper.SetDefault("cache.redis.pool_size", 0)
+	viper.SetDefault("cache.redis.key_prefix", "")
+	viper.SetDefault("cache.redis.codec", "json")
+	viper.SetDefault("health.event_bus_threshold", 0.9)
+	viper.SetDefault("admin.token", "")
+	viper.SetDefault("modules.enabled", []string{})
+	viper.SetDefault("database.auto_migrate", true)
+	viper.SetDefault("event_bus.workers", 4)
+	viper.SetDefault("event_bus.handler_timeout", 30)
+	viper.SetDefault("event_bus.request_timeout", 10)
+	viper.SetDefault("event_bus.buffer_size", 100)
+	viper.SetDefault("event_bus.overflow", "block")
+	viper.SetDefault("event_bus.block_timeout", 0)
+	viper.SetDefault("event_bus.spill_path", "data/event-spill.jsonl")
+	viper.SetDefault("event_bus.transport", "memory")
+	viper.SetDefault("event_bus.database.group", "")
+	viper.SetDefault("event_bus.database.poll_interval", 1000)
+	viper.SetDefault("event_bus.database.batch_size", 100)
+	viper.SetDefault("event_bus.database.settle_delay", 1000)
+	viper.SetDefault("event_bus.database.retention", 168)
+	viper.SetDefault("event_bus.schedule.poll_interval", 1000)
+	viper.SetDefault("event_bus.store.enabled", false)
+	viper.SetDefault("event_bus.retry.max_attempts", 3)
+	viper.SetDefault("event_bus.retry.initial_backoff", 100)
+	viper.SetDefault("event_bus.retry.max_backoff", 10000)
+	viper.SetDefault("event_bus.retry.multiplier", 2.0)
+	viper.SetDefault("event_bus.retry.jitter", 0.2)
+	viper.SetDefault("outbox.poll_interval", 1)
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("outbox.max_attempts", 10)
+}
+
 func checkKey(key string) {
 	if !viper.IsSet(key) {
 		log.Fatalf("Configuration key %s not found; aborting \n", key)
@@ -48,6 +91,17 @@ func checkKey(key string) {
 	}
 }
 
+// Missing returns the keys that are not set in the configuration
+func Missing(keys ...string) []string {
+	missing := make([]string, 0)
+	for _, key := range keys {
+		if !viper.IsSet(key) {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
 func GetString(key string) string {
 	checkKey(key)
 	return viper.GetString(key)
@@ -63,10 +117,18 @@ func GetBool(key string) bool {
 	return viper.GetBool(key)
 }
 
-func GetJWTService() jwt.JWT {
-	signatureKey := GetString("jwt.signature_key")
-	if signatureKey == "" {
-		panic("JWT signature key not found in configuration")
-	}
-	return jwt.NewJWTImpl(signatureKey, 7)
+func GetFloat64(key string) float64 {
+	checkKey(key)
+	return viper.GetFloat64(key)
+}
+
+func GetStringSlice(key string) []string {
+	checkKey(key)
+	return viper.GetStringSlice(key)
+}
+
+// UnmarshalKey decodes the section under key into v using its mapstructure
+// tags; fields missing from the file keep their current values
+func UnmarshalKey(key string, v interface{}) error {
+	return viper.UnmarshalKey(key, v)
 }
@@ -0,0 +1,71 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Errors
+var (
+	ErrServiceNotFound = errors.New("service not found")
+	ErrServiceExists   = errors.New("service already provided")
+	ErrNilService      = errors.New("service cannot be nil")
+)
+
+// Container holds the services modules publish for each other. Services are
+// keyed by the interface type they are provided as, so consumers only ever
+// depend on the interface and never on the providing module.
+type Container struct {
+	mu       sync.RWMutex
+	services map[reflect.Type]interface{}
+}
+
+// New creates an empty container
+func New() *Container {
+	return &Container{
+		services: make(map[reflect.Type]interface{}),
+	}
+}
+
+// Provide registers service under the type T, usually an interface
+func Provide[T any](c *Container, service T) error {
+	key := typeOf[T]()
+
+	value := reflect.ValueOf(&service).Elem()
+	if value.Kind() == reflect.Interface && value.IsNil() {
+		return fmt.Errorf("%w: %s", ErrNilService, key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.services[key]; exists {
+		return fmt.Errorf("%w: %s", ErrServiceExists, key)
+	}
+	c.services[key] = service
+
+	return nil
+}
+
+// Resolve returns the service provided under the type T
+func Resolve[T any](c *Container) (T, error) {
+	key := typeOf[T]()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	service, exists := c.services[key]
+	if !exists {
+		var zero T
+		return zero, fmt.Errorf("%w: %s", ErrServiceNotFound, key)
+	}
+
+	return service.(T), nil
+}
+
+// typeOf returns the reflect type of T, including interface types
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
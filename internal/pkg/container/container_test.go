@@ -0,0 +1,40 @@
+package container
+
+import (
+	"errors"
+	"testing"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type englishGreeter struct{}
+
+func (englishGreeter) Greet() string { return "hello" }
+
+func TestContainer(t *testing.T) {
+	c := New()
+
+	if _, err := Resolve[greeter](c); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("expected service not found, got %v", err)
+	}
+
+	if err := Provide[greeter](c, englishGreeter{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Provide[greeter](c, englishGreeter{}); !errors.Is(err, ErrServiceExists) {
+		t.Errorf("expected service exists, got %v", err)
+	}
+	if err := Provide[greeter](c, nil); !errors.Is(err, ErrNilService) {
+		t.Errorf("expected nil service, got %v", err)
+	}
+
+	service, err := Resolve[greeter](c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if service.Greet() != "hello" {
+		t.Errorf("resolved the wrong service")
+	}
+}
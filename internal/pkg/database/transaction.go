@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction started by Transaction
+type txKey struct{}
+
+// Transaction runs fn in a database transaction, committing when fn returns
+// nil. Repositories that get their connection from Conn(ctx) with the ctx
+// passed to fn join the transaction. A nested call joins the outer one.
+func Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, exists := ctx.Value(txKey{}).(*gorm.DB); exists {
+		return fn(ctx)
+	}
+
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction carried by ctx, or the shared connection
+// when ctx is not part of a transaction
+func Conn(ctx context.Context) *gorm.DB {
+	if tx, exists := ctx.Value(txKey{}).(*gorm.DB); exists {
+		return tx
+	}
+	return DB.WithContext(ctx)
+}
@@ -0,0 +1,158 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"go-modular/internal/pkg/bus"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo"
+	"gorm.io/gorm"
+)
+
+// Statuses
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc reports an error when a component is unhealthy
+type CheckFunc func(ctx context.Context) error
+
+// Component is the result of a single check
+type Component struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the aggregated result of every check
+type Report struct {
+	Status     string      `json:"status"`
+	Components []Component `json:"components"`
+}
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// Registry runs a set of named checks concurrently
+type Registry struct {
+	mu      sync.RWMutex
+	checks  []namedCheck
+	timeout time.Duration
+}
+
+// NewRegistry creates a registry where each check gets at most timeout to answer
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register adds a named check
+func (r *Registry) Register(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, namedCheck{name: name, check: check})
+}
+
+// Run runs every check and reports down if any of them fails
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make([]namedCheck, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	report := Report{
+		Status:     StatusUp,
+		Components: make([]Component, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c namedCheck) {
+			defer wg.Done()
+			report.Components[i] = r.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, component := range report.Components {
+		if component.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+// run runs a single check within the registry timeout
+func (r *Registry) run(ctx context.Context, c namedCheck) Component {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				result <- fmt.Errorf("check panicked: %v", recovered)
+			}
+		}()
+		result <- c.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	component := Component{
+		Name:      c.name,
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		component.Status = StatusDown
+		component.Error = err.Error()
+	}
+
+	return component
+}
+
+// Handler serves the report, with 503 Service Unavailable when it is down
+func (r *Registry) Handler(c echo.Context) error {
+	report := r.Run(c.Request().Context())
+	if report.Status != StatusUp {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// Database checks that the database answers a ping
+func Database(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// EventBus fails when one of the bus queues is filled to threshold, a
+// fraction of its capacity
+func EventBus(b *bus.EventBus, threshold float64) CheckFunc {
+	return func(ctx context.Context) error {
+		if saturation := b.Saturation(); saturation >= threshold {
+			return fmt.Errorf("event queue %.0f%% full, %d of %d events pending overall", saturation*100, b.Pending(), b.Capacity())
+		}
+		return nil
+	}
+}
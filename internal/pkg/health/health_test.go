@@ -0,0 +1,39 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(50 * time.Millisecond)
+	r.Register("ok", func(ctx context.Context) error { return nil })
+
+	if report := r.Run(context.Background()); report.Status != StatusUp {
+		t.Fatalf("expected up, got %+v", report)
+	}
+
+	r.Register("failing", func(ctx context.Context) error { return errors.New("broken") })
+	r.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	report := r.Run(context.Background())
+	if report.Status != StatusDown {
+		t.Fatalf("expected down, got %+v", report)
+	}
+
+	want := []string{StatusUp, StatusDown, StatusDown}
+	for i, component := range report.Components {
+		if component.Status != want[i] {
+			t.Errorf("%s: expected %s, got %s", component.Name, want[i], component.Status)
+		}
+	}
+	if report.Components[2].Error != context.DeadlineExceeded.Error() {
+		t.Errorf("expected slow check to time out, got %q", report.Components[2].Error)
+	}
+}
@@ -0,0 +1,83 @@
+// Package metrics exposes application metrics in the Prometheus text format
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo"
+)
+
+// Kinds of metric
+const (
+	KindCounter = "counter"
+	KindGauge   = "gauge"
+)
+
+// ValueFunc reads the current value of a metric
+type ValueFunc func() float64
+
+type metric struct {
+	name  string
+	help  string
+	kind  string
+	value ValueFunc
+}
+
+// Registry holds metrics whose values are read when they are scraped
+type Registry struct {
+	mu      sync.RWMutex
+	metrics map[string]metric
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+// CounterFunc registers a counter, a value that only goes up, such as
+// "event_bus_dropped_total". A metric registered again replaces the old one.
+func (r *Registry) CounterFunc(name, help string, value ValueFunc) {
+	r.register(metric{name: name, help: help, kind: KindCounter, value: value})
+}
+
+// GaugeFunc registers a gauge, a value that goes up and down
+func (r *Registry) GaugeFunc(name, help string, value ValueFunc) {
+	r.register(metric{name: name, help: help, kind: KindGauge, value: value})
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics[m.name] = m
+}
+
+// Write renders every metric, sorted by name
+func (r *Registry) Write(b *strings.Builder) {
+	r.mu.RLock()
+	metrics := make([]metric, 0, len(r.metrics))
+	for _, m := range r.metrics {
+		metrics = append(metrics, m)
+	}
+	r.mu.RUnlock()
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].name < metrics[j].name
+	})
+
+	for _, m := range metrics {
+		fmt.Fprintf(b, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(b, "# TYPE %s %s\n", m.name, m.kind)
+		fmt.Fprintf(b, "%s %s\n", m.name, strconv.FormatFloat(m.value(), 'g', -1, 64))
+	}
+}
+
+// Handler serves the metrics for Prometheus to scrape
+func (r *Registry) Handler(c echo.Context) error {
+	var b strings.Builder
+	r.Write(&b)
+	return c.Blob(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
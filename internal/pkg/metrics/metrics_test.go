@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+	dropped := 0.0
+	r.CounterFunc("events_dropped_total", "Events dropped.", func() float64 { return dropped })
+	r.GaugeFunc("events_pending", "Events waiting.", func() float64 { return 1.5 })
+
+	dropped = 3
+	var b strings.Builder
+	r.Write(&b)
+
+	want := "# HELP events_dropped_total Events dropped.\n" +
+		"# TYPE events_dropped_total counter\n" +
+		"events_dropped_total 3\n" +
+		"# HELP events_pending Events waiting.\n" +
+		"# TYPE events_pending gauge\n" +
+		"events_pending 1.5\n"
+	if b.String() != want {
+		t.Errorf("unexpected output:\n%s", b.String())
+	}
+}
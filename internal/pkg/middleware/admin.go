@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+// AdminToken only lets through requests carrying token, either as a Bearer
+// token or in the X-Admin-Token header
+func AdminToken(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			provided := c.Request().Header.Get("X-Admin-Token")
+			if provided == "" {
+				provided = strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+			}
+
+			if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+					"error":   "Invalid admin token",
+					"message": "Unauthorized",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -32,6 +32,14 @@ func Auth(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
+		// the auth module sets the service; without it no token can be trusted
+		if jwtService == nil {
+			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"error":   "Authentication is not configured",
+				"message": "Unauthorized",
+			})
+		}
+
 		token := strings.TrimPrefix(authHeader, "Bearer ")
 
 		claims, err := jwtService.ValidateToken(token)
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"go-modular/internal/pkg/bus"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo"
+)
+
+// CorrelationHeader carries the correlation ID of a request
+const CorrelationHeader = "X-Correlation-ID"
+
+// Correlation takes the correlation ID from the X-Correlation-ID or
+// X-Request-ID header, or generates one, echoes it in the response and puts
+// it in the request context so the events published while handling the
+// request carry it
+func Correlation() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(CorrelationHeader)
+			if id == "" {
+				id = req.Header.Get(echo.HeaderXRequestID)
+			}
+			if id == "" {
+				id = uuid.NewString()
+			}
+
+			c.Response().Header().Set(CorrelationHeader, id)
+			c.SetRequest(req.WithContext(bus.WithCorrelationID(req.Context(), id)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo"
+)
+
+var (
+	groupsMu sync.RWMutex
+	groups   = make(map[*echo.Echo]map[string][]string)
+)
+
+// Group creates a route group like echo.Echo.Group and remembers the names of
+// its middleware so the admin API can show the chain of every route
+func Group(e *echo.Echo, prefix string, m ...echo.MiddlewareFunc) *echo.Group {
+	names := make([]string, len(m))
+	for i, mw := range m {
+		names[i] = Name(mw)
+	}
+
+	groupsMu.Lock()
+	if groups[e] == nil {
+		groups[e] = make(map[string][]string)
+	}
+	groups[e][prefix] = names
+	groupsMu.Unlock()
+
+	return e.Group(prefix, m...)
+}
+
+// Chain returns the names of the group middleware wrapping path, taken from
+// the longest group prefix created with Group that matches it
+func Chain(e *echo.Echo, path string) []string {
+	groupsMu.RLock()
+	defer groupsMu.RUnlock()
+
+	longest := ""
+	var chain []string
+	for prefix, names := range groups[e] {
+		matches := path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+		if matches && len(prefix) >= len(longest) {
+			longest, chain = prefix, names
+		}
+	}
+
+	return chain
+}
+
+// Name returns a readable name for a middleware or handler function, such
+// as "middleware.Auth"
+func Name(fn interface{}) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+
+	// closures returned by constructors are named like pkg.Constructor.func1
+	for i := strings.Index(name, ".func"); i > 0; i = strings.Index(name, ".func") {
+		name = name[:i]
+	}
+
+	return name
+}
@@ -0,0 +1,238 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Errors
+var (
+	ErrInvalidVersion   = errors.New("migration version must be positive")
+	ErrDuplicateVersion = errors.New("duplicate migration version")
+	ErrIrreversible     = errors.New("migration cannot be rolled back")
+	ErrUnknownVersion   = errors.New("applied migration is not defined")
+)
+
+// Migration is a single versioned schema change owned by a module
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SQL builds a migration from raw SQL. An empty down statement makes the
+// migration irreversible.
+func SQL(version int64, name, up, down string) Migration {
+	m := Migration{
+		Version: version,
+		Name:    name,
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec(up).Error
+		},
+	}
+	if down != "" {
+		m.Down = func(tx *gorm.DB) error {
+			return tx.Exec(down).Error
+		}
+	}
+	return m
+}
+
+// Record is a row of the schema_migrations table
+type Record struct {
+	Module    string    `gorm:"primaryKey;size:100"`
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for Record
+func (*Record) TableName() string {
+	return "schema_migrations"
+}
+
+// Status describes a migration and whether it has been applied
+type Status struct {
+	Module    string     `json:"module"`
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrator applies and rolls back module migrations, tracking applied
+// versions per module in the schema_migrations table
+type Migrator struct {
+	db *gorm.DB
+}
+
+// NewMigrator creates a new migrator
+func NewMigrator(db *gorm.DB) *Migrator {
+	return &Migrator{db: db}
+}
+
+// Up applies every pending migration of module in version order and
+// returns the migrations that were applied. It stops at the first failure.
+func (m *Migrator) Up(ctx context.Context, module string, migrations []Migration) ([]Migration, error) {
+	sorted, err := sortMigrations(migrations)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", module, err)
+	}
+
+	applied, err := m.applied(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]Migration, 0)
+	for _, migration := range sorted {
+		if _, exists := applied[migration.Version]; exists {
+			continue
+		}
+
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&Record{
+				Module:    module,
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("%s: migration %d_%s failed: %w", module, migration.Version, migration.Name, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down rolls back every applied migration of module newer than target,
+// newest first, and returns the migrations that were rolled back. Use a
+// target of 0 to roll back everything.
+func (m *Migrator) Down(ctx context.Context, module string, migrations []Migration, target int64) ([]Migration, error) {
+	sorted, err := sortMigrations(migrations)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", module, err)
+	}
+
+	applied, err := m.applied(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]Migration, len(sorted))
+	for _, migration := range sorted {
+		byVersion[migration.Version] = migration
+	}
+
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		if version > target {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	done := make([]Migration, 0)
+	for _, version := range versions {
+		migration, exists := byVersion[version]
+		if !exists {
+			return done, fmt.Errorf("%s: %w: %d", module, ErrUnknownVersion, version)
+		}
+		if migration.Down == nil {
+			return done, fmt.Errorf("%s: %w: %d_%s", module, ErrIrreversible, migration.Version, migration.Name)
+		}
+
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Where("module = ? AND version = ?", module, migration.Version).Delete(&Record{}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("%s: rollback of %d_%s failed: %w", module, migration.Version, migration.Name, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Status lists the migrations of module and whether each one is applied
+func (m *Migrator) Status(ctx context.Context, module string, migrations []Migration) ([]Status, error) {
+	sorted, err := sortMigrations(migrations)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", module, err)
+	}
+
+	applied, err := m.applied(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(sorted))
+	for i, migration := range sorted {
+		statuses[i] = Status{
+			Module:  module,
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		if record, exists := applied[migration.Version]; exists {
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &record.AppliedAt
+		}
+	}
+
+	return statuses, nil
+}
+
+// applied returns the applied migrations of module keyed by version,
+// creating the schema_migrations table on first use
+func (m *Migrator) applied(ctx context.Context, module string) (map[int64]Record, error) {
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(&Record{}); err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	if err := db.Where("module = ?", module).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+// sortMigrations validates migrations and returns a copy sorted by version
+func sortMigrations(migrations []Migration) ([]Migration, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, migration := range sorted {
+		if migration.Version <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidVersion, migration.Name)
+		}
+		if i > 0 && sorted[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, migration.Version)
+		}
+	}
+
+	return sorted, nil
+}
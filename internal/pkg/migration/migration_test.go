@@ -0,0 +1,119 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	// every connection to :memory: is a separate database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	return db
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		SQL(2, "add_notes_title", "ALTER TABLE notes ADD COLUMN title TEXT", "ALTER TABLE notes DROP COLUMN title"),
+		SQL(1, "create_notes", "CREATE TABLE notes (id INTEGER PRIMARY KEY)", "DROP TABLE notes"),
+	}
+}
+
+func TestMigratorUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrator := NewMigrator(db)
+
+	applied, err := migrator.Up(ctx, "notes", testMigrations())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(applied) != 2 || applied[0].Version != 1 {
+		t.Fatalf("expected both migrations applied in order, got %v", applied)
+	}
+	if !db.Migrator().HasColumn("notes", "title") {
+		t.Errorf("column title was not created")
+	}
+
+	// applying again is a no-op
+	if applied, _ := migrator.Up(ctx, "notes", testMigrations()); len(applied) != 0 {
+		t.Errorf("expected nothing to apply, got %d", len(applied))
+	}
+
+	rolledBack, err := migrator.Down(ctx, "notes", testMigrations(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rolledBack) != 1 || rolledBack[0].Version != 2 {
+		t.Fatalf("expected version 2 rolled back, got %v", rolledBack)
+	}
+	if db.Migrator().HasColumn("notes", "title") {
+		t.Errorf("column title was not dropped")
+	}
+
+	statuses, err := migrator.Status(ctx, "notes", testMigrations())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !statuses[0].Applied || statuses[1].Applied {
+		t.Errorf("unexpected status: %+v", statuses)
+	}
+}
+
+func TestMigratorFailureStopsAndRollsBack(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrator := NewMigrator(db)
+
+	migrations := []Migration{
+		SQL(1, "create_notes", "CREATE TABLE notes (id INTEGER PRIMARY KEY)", "DROP TABLE notes"),
+		SQL(2, "broken", "ALTER TABLE missing ADD COLUMN title TEXT", ""),
+		SQL(3, "never_reached", "CREATE TABLE tags (id INTEGER PRIMARY KEY)", "DROP TABLE tags"),
+	}
+
+	applied, err := migrator.Up(ctx, "notes", migrations)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if len(applied) != 1 {
+		t.Errorf("expected only the first migration applied, got %d", len(applied))
+	}
+	if db.Migrator().HasTable("tags") {
+		t.Errorf("migrations after the failure must not run")
+	}
+
+	statuses, _ := migrator.Status(ctx, "notes", migrations)
+	if statuses[1].Applied {
+		t.Errorf("failed migration recorded as applied")
+	}
+}
+
+func TestMigratorValidation(t *testing.T) {
+	migrator := NewMigrator(openTestDB(t))
+
+	duplicate := []Migration{
+		SQL(1, "a", "SELECT 1", ""),
+		SQL(1, "b", "SELECT 1", ""),
+	}
+	if _, err := migrator.Up(context.Background(), "notes", duplicate); !errors.Is(err, ErrDuplicateVersion) {
+		t.Errorf("expected duplicate version error, got %v", err)
+	}
+
+	irreversible := []Migration{SQL(1, "a", "SELECT 1", "")}
+	if _, err := migrator.Up(context.Background(), "notes", irreversible); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := migrator.Down(context.Background(), "notes", irreversible, 0); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("expected irreversible error, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/database"
+	"go-modular/internal/pkg/migration"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Message is a row of the outbox_messages table
+type Message struct {
+	ID            uint64 `gorm:"primaryKey"`
+	EventType     string `gorm:"size:255;not null"`
+	Payload       string `gorm:"type:text;not null"`
+	EventID       string `gorm:"size:36"`
+	Source        string `gorm:"size:100"`
+	CorrelationID string `gorm:"size:100"`
+	CausationID   string `gorm:"size:36"`
+	Version       int    `gorm:"not null;default:1"`
+	OccurredAt    *time.Time
+	PartitionKey  string     `gorm:"size:255"`
+	Attempts      int        `gorm:"not null;default:0"`
+	LastError     string     `gorm:"size:1024"`
+	CreatedAt     time.Time  `gorm:"not null"`
+	SentAt        *time.Time `gorm:"index"`
+	// LockedUntil holds the message for the relay delivering it
+	LockedUntil *time.Time
+}
+
+// TableName specifies the table name for Message
+func (*Message) TableName() string {
+	return "outbox_messages"
+}
+
+// messageV1 is the outbox_messages table as version 1 created it
+type messageV1 struct {
+	ID        uint64     `gorm:"primaryKey"`
+	EventType string     `gorm:"size:255;not null"`
+	Payload   string     `gorm:"type:text;not null"`
+	Attempts  int        `gorm:"not null;default:0"`
+	LastError string     `gorm:"size:1024"`
+	CreatedAt time.Time  `gorm:"not null"`
+	SentAt    *time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for messageV1
+func (*messageV1) TableName() string {
+	return "outbox_messages"
+}
+
+// metadataColumns are the event metadata columns added by version 2
+var metadataColumns = []string{"EventID", "Source", "CorrelationID", "CausationID", "Version", "OccurredAt"}
+
+// Migrations returns the schema of the outbox, applied by the application
+// before any module migration
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		{
+			Version: 1,
+			Name:    "create_outbox_messages_table",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&messageV1{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&messageV1{})
+			},
+		},
+		{
+			Version: 2,
+			Name:    "add_outbox_event_metadata",
+			Up: func(tx *gorm.DB) error {
+				for _, column := range metadataColumns {
+					if err := tx.Migrator().AddColumn(&Message{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Down: func(tx *gorm.DB) error {
+				for _, column := range metadataColumns {
+					if err := tx.Migrator().DropColumn(&Message{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version: 3,
+			Name:    "add_outbox_partition_key",
+			Up: func(tx *gorm.DB) error {
+				return tx.Migrator().AddColumn(&Message{}, "PartitionKey")
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&Message{}, "PartitionKey")
+			},
+		},
+		{
+			Version: 4,
+			Name:    "add_outbox_lease",
+			Up: func(tx *gorm.DB) error {
+				return tx.Migrator().AddColumn(&Message{}, "LockedUntil")
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&Message{}, "LockedUntil")
+			},
+		},
+	}
+}
+
+// Enqueue stores an event on topic in the outbox, with the metadata it
+// would get if published on eventBus from ctx. Call it with the ctx of
+// database.Transaction so the event is only recorded, and later published
+// by the Relay, if the surrounding domain change commits.
+func Enqueue[T any](ctx context.Context, eventBus *bus.EventBus, topic bus.Topic[T], payload T) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding %s payload: %w", topic.Name(), err)
+	}
+
+	metadata := bus.NewMetadata(ctx, eventBus.Source(), topic.Version())
+	return database.Conn(ctx).Create(&Message{
+		EventType:     topic.Name(),
+		Payload:       string(data),
+		EventID:       metadata.ID,
+		Source:        metadata.Source,
+		CorrelationID: metadata.CorrelationID,
+		CausationID:   metadata.CausationID,
+		Version:       metadata.Version,
+		OccurredAt:    &metadata.OccurredAt,
+		PartitionKey:  topic.Key(payload),
+		CreatedAt:     time.Now(),
+	}).Error
+}
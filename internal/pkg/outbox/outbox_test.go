@@ -0,0 +1,266 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/database"
+	"go-modular/internal/pkg/logger"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+type noteCreated struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+var noteCreatedTopic = bus.NewTopic[noteCreated]("note.created")
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	// every connection to :memory: is a separate database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, m := range Migrations() {
+		if err := m.Up(db); err != nil {
+			t.Fatalf("failed to migrate: %v", err)
+		}
+	}
+
+	database.DB = db
+	return db
+}
+
+func newTestRelay(t *testing.T, db *gorm.DB, eventBus *bus.EventBus) *Relay {
+	cfg := logger.DefaultConfig()
+	cfg.OutputPath = filepath.Join(t.TempDir(), "test.log")
+	log, err := logger.NewLogger(cfg, "outbox")
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	return NewRelay(db, eventBus, log, time.Second, 10, 2)
+}
+
+func pending(t *testing.T, db *gorm.DB) []Message {
+	var messages []Message
+	if err := db.Where("sent_at IS NULL").Order("id").Find(&messages).Error; err != nil {
+		t.Fatalf("failed to load messages: %v", err)
+	}
+	return messages
+}
+
+func TestEnqueueFollowsTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	eventBus := bus.NewEventBus()
+
+	rollback := errors.New("rollback")
+	err := database.Transaction(ctx, func(ctx context.Context) error {
+		if err := Enqueue(ctx, eventBus, noteCreatedTopic, noteCreated{ID: 1}); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+	if messages := pending(t, db); len(messages) != 0 {
+		t.Fatalf("rolled back event was recorded: %+v", messages)
+	}
+
+	err = database.Transaction(ctx, func(ctx context.Context) error {
+		return Enqueue(ctx, eventBus, noteCreatedTopic, noteCreated{ID: 2})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if messages := pending(t, db); len(messages) != 1 || messages[0].EventType != "note.created" {
+		t.Fatalf("expected one note.created message, got %+v", messages)
+	}
+}
+
+func TestRelayDeliversTypedPayloads(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	eventBus := bus.NewEventBus()
+
+	var got []noteCreated
+	if _, err := bus.Subscribe(eventBus, noteCreatedTopic, func(ctx context.Context, event noteCreated) error {
+		got = append(got, event)
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if err := Enqueue(ctx, eventBus, noteCreatedTopic, noteCreated{ID: i, Title: "note"}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	sent, err := newTestRelay(t, db, eventBus).Flush(ctx)
+	if err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if sent != 3 || len(got) != 3 || got[0].ID != 1 || got[2].ID != 3 {
+		t.Fatalf("expected notes 1-3 in order, sent %d, got %+v", sent, got)
+	}
+	if messages := pending(t, db); len(messages) != 0 {
+		t.Fatalf("delivered messages still pending: %+v", messages)
+	}
+}
+
+func TestRelayKeepsEventMetadata(t *testing.T) {
+	ctx := bus.WithCorrelationID(context.Background(), "request-1")
+	db := openTestDB(t)
+	eventBus := bus.NewEventBus()
+
+	var got bus.Event
+	if _, err := bus.Subscribe(eventBus, noteCreatedTopic, func(ctx context.Context, event noteCreated) error {
+		got, _ = bus.EventFromContext(ctx)
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	notes := eventBus.WithSource("notes")
+	topic := noteCreatedTopic.WithVersion(2).WithKey(func(note noteCreated) string {
+		return strconv.Itoa(note.ID)
+	})
+	if err := Enqueue(ctx, notes, topic, noteCreated{ID: 1}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	recorded := pending(t, db)[0]
+
+	if _, err := newTestRelay(t, db, eventBus).Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if got.ID == "" || got.ID != recorded.EventID {
+		t.Errorf("expected event ID %q, got %q", recorded.EventID, got.ID)
+	}
+	if got.Source != "notes" || got.CorrelationID != "request-1" || got.Version != 2 || got.Key != "1" {
+		t.Errorf("unexpected metadata %+v", got.Metadata)
+	}
+	if got.OccurredAt.IsZero() {
+		t.Errorf("occurred at was not kept")
+	}
+}
+
+func TestRelayRetriesFailedDeliveries(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	eventBus := bus.NewEventBus(bus.WithErrorFunc(func(bus.Event, string, error) {}))
+
+	calls := 0
+	if _, err := bus.Subscribe(eventBus, noteCreatedTopic, func(ctx context.Context, event noteCreated) error {
+		calls++
+		return errors.New("unavailable")
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	if err := Enqueue(ctx, eventBus, noteCreatedTopic, noteCreated{ID: 1}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	relay := newTestRelay(t, db, eventBus)
+	for i := 0; i < 3; i++ {
+		if sent, err := relay.Flush(ctx); err != nil || sent != 0 {
+			t.Fatalf("expected nothing sent, got %d, %v", sent, err)
+		}
+	}
+
+	// the message is given up after max attempts
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+	messages := pending(t, db)
+	if len(messages) != 1 || messages[0].Attempts != 2 || messages[0].LastError == "" {
+		t.Fatalf("expected failed message with 2 attempts, got %+v", messages)
+	}
+}
+
+func TestRelaySkipsClaimedMessages(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	eventBus := bus.NewEventBus()
+
+	var got []int
+	if _, err := bus.Subscribe(eventBus, noteCreatedTopic, func(ctx context.Context, event noteCreated) error {
+		got = append(got, event.ID)
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	for i := 1; i <= 2; i++ {
+		if err := Enqueue(ctx, eventBus, noteCreatedTopic, noteCreated{ID: i}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	// another relay is delivering the first message
+	first := pending(t, db)[0]
+	held := time.Now().Add(time.Minute)
+	db.Model(&first).Update("locked_until", &held)
+
+	relay := newTestRelay(t, db, eventBus)
+	if sent, err := relay.Flush(ctx); err != nil || sent != 1 {
+		t.Fatalf("expected 1 message sent, got %d, %v", sent, err)
+	}
+	if len(got) != 1 || got[0] != 2 {
+		t.Fatalf("expected only note 2, got %v", got)
+	}
+
+	// the other relay died and its lease ran out
+	expired := time.Now().Add(-time.Second)
+	db.Model(&first).Update("locked_until", &expired)
+	if sent, err := relay.Flush(ctx); err != nil || sent != 1 {
+		t.Fatalf("expected the expired message sent, got %d, %v", sent, err)
+	}
+	if len(got) != 2 || got[1] != 1 {
+		t.Errorf("expected note 1 after its lease expired, got %v", got)
+	}
+}
+
+func TestRelayLeavesMessagesTakenOver(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	eventBus := bus.NewEventBus()
+
+	// the lease runs out during delivery and another relay claims the message
+	var takenOver time.Time
+	if _, err := bus.Subscribe(eventBus, noteCreatedTopic, func(ctx context.Context, event noteCreated) error {
+		takenOver = time.Now().Add(2 * time.Minute).Truncate(time.Millisecond)
+		return db.Model(&Message{}).Where("id = ?", 1).Update("locked_until", &takenOver).Error
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := Enqueue(ctx, eventBus, noteCreatedTopic, noteCreated{ID: 1}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	relay := newTestRelay(t, db, eventBus)
+	if sent, err := relay.Flush(ctx); err != nil || sent != 0 {
+		t.Fatalf("expected nothing sent, got %d, %v", sent, err)
+	}
+
+	messages := pending(t, db)
+	if len(messages) != 1 || messages[0].Attempts != 0 {
+		t.Fatalf("expected the message left unsent, got %+v", messages)
+	}
+	if lockedUntil := messages[0].LockedUntil; lockedUntil == nil || !lockedUntil.Equal(takenOver) {
+		t.Errorf("expected the other relay's lease kept, got %v", lockedUntil)
+	}
+}
@@ -0,0 +1,199 @@
+package outbox
+
+import (
+	"context"
+	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/logger"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// messageLease is how long a relay holds a message it is delivering before
+// another relay may take it over
+const messageLease = time.Minute
+
+// unclaimed selects the messages no relay is holding
+const unclaimed = "locked_until IS NULL OR locked_until < ?"
+
+// held selects a message still under the lease written by claim
+const held = "id = ? AND locked_until = ?"
+
+// Relay publishes pending outbox messages to the event bus. A message is
+// marked sent only after every handler has run, or once a durable transport
+// has stored it, so delivery is at least once: a crash in between delivers
+// it again, and so does a failing handler unless the bus dead-letters it.
+//
+// Every replica can run a relay: each message is claimed before it is
+// delivered, so relays sharing a database do not deliver it twice. A relay
+// whose lease ran out during delivery leaves the message to the relay that
+// took it over.
+type Relay struct {
+	db          *gorm.DB
+	bus         *bus.EventBus
+	logger      *logger.Logger
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+// NewRelay creates a relay polling db every interval for at most batchSize
+// messages. Messages that failed maxAttempts times are left for an operator.
+func NewRelay(db *gorm.DB, eventBus *bus.EventBus, log *logger.Logger, interval time.Duration, batchSize, maxAttempts int) *Relay {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Relay{
+		db:          db,
+		bus:         eventBus,
+		logger:      log,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run relays messages until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Flush(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("Outbox relay failed", "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush delivers one batch of pending messages in insertion order and
+// returns how many were sent. Messages claimed by another relay are skipped.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	var messages []Message
+	err := r.db.WithContext(ctx).
+		Where("sent_at IS NULL AND attempts < ?", r.maxAttempts).
+		Where(unclaimed, time.Now()).
+		Order("id").
+		Limit(r.batchSize).
+		Find(&messages).Error
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range messages {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+
+		message := &messages[i]
+		claimed, err := r.claim(ctx, message)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := r.deliver(ctx, message); err != nil {
+			r.logger.Warn("Outbox message not delivered", "id", message.ID, "event", message.EventType, "error", err.Error())
+			if _, err := r.markFailed(ctx, message, err); err != nil {
+				return sent, err
+			}
+			continue
+		}
+
+		kept, err := r.markSent(ctx, message)
+		if err != nil {
+			return sent, err
+		}
+		if !kept {
+			r.logger.Warn("Outbox message lease expired during delivery", "id", message.ID, "event", message.EventType)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// claim holds a message for messageLease, and reports false when another
+// relay holds it or has sent it already. The lease is kept in
+// message.LockedUntil, truncated to what every database stores, so the
+// updates after delivery can check it.
+func (r *Relay) claim(ctx context.Context, message *Message) (bool, error) {
+	now := time.Now()
+	lockedUntil := now.Add(messageLease).Truncate(time.Millisecond)
+	result := r.db.WithContext(ctx).Model(&Message{}).
+		Where("id = ? AND sent_at IS NULL", message.ID).
+		Where(unclaimed, now).
+		Update("locked_until", &lockedUntil)
+	if result.Error != nil || result.RowsAffected != 1 {
+		return false, result.Error
+	}
+	message.LockedUntil = &lockedUntil
+	return true, nil
+}
+
+// deliver decodes a message into the payload type bound to its event name
+// and forwards it to the bus
+func (r *Relay) deliver(ctx context.Context, message *Message) error {
+	event, err := r.bus.Decode(message.EventType, []byte(message.Payload))
+	if err != nil {
+		return err
+	}
+
+	// messages recorded before version 2 get fresh metadata from the bus
+	if message.EventID != "" {
+		event.Metadata = bus.Metadata{
+			ID:            message.EventID,
+			Source:        message.Source,
+			CorrelationID: message.CorrelationID,
+			CausationID:   message.CausationID,
+			Version:       message.Version,
+			Key:           message.PartitionKey,
+		}
+		if message.OccurredAt != nil {
+			event.OccurredAt = *message.OccurredAt
+		}
+	}
+	return r.bus.Forward(ctx, event)
+}
+
+// markSent records a delivered message, and reports false when its claim
+// was lost to another relay
+func (r *Relay) markSent(ctx context.Context, message *Message) (bool, error) {
+	now := time.Now()
+	return r.release(ctx, message, map[string]interface{}{
+		"sent_at":  &now,
+		"attempts": message.Attempts + 1,
+	})
+}
+
+// markFailed records a failed delivery, and reports false when its claim
+// was lost to another relay
+func (r *Relay) markFailed(ctx context.Context, message *Message, cause error) (bool, error) {
+	lastError := cause.Error()
+	if len(lastError) > 1024 {
+		lastError = lastError[:1024]
+	}
+	return r.release(ctx, message, map[string]interface{}{
+		"attempts":   message.Attempts + 1,
+		"last_error": lastError,
+	})
+}
+
+// release applies updates and drops the claim, unless the lease written by
+// claim was replaced in the meantime
+func (r *Relay) release(ctx context.Context, message *Message, updates map[string]interface{}) (bool, error) {
+	updates["locked_until"] = nil
+	result := r.db.WithContext(ctx).Model(&Message{}).
+		Where(held, message.ID, message.LockedUntil).
+		Updates(updates)
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,206 @@
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Errors
+var (
+	ErrInvalidName   = errors.New("module name must be lowercase letters and digits, starting with a letter")
+	ErrReservedName  = errors.New("module name clashes with a package used by the generated code")
+	ErrModuleExists  = errors.New("module directory already exists")
+	ErrNoModulePath  = errors.New("module path not found in go.mod")
+	ErrNoImportBlock = errors.New("no module imports found in main.go")
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+var validName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// reserved are identifiers the generated files already use as package names
+var reserved = map[string]bool{
+	"app": true, "bus": true, "container": true, "context": true, "database": true,
+	"echo": true, "entity": true, "errors": true, "gorm": true, "handler": true,
+	"http": true, "logger": true, "middleware": true, "migration": true, "repository": true,
+	"request": true, "response": true, "service": true, "strconv": true, "testing": true,
+	"time": true,
+}
+
+// files maps each template to the path it is rendered to, relative to the module directory
+var files = map[string]string{
+	"module.go.tmpl":          "module.go",
+	"migrations.go.tmpl":      "migrations.go",
+	"entity.go.tmpl":          "domain/entity/{{.Name}}.go",
+	"repository.go.tmpl":      "domain/repository/{{.Name}}_repository.go",
+	"repository_impl.go.tmpl": "domain/repository/{{.Name}}_repository_impl.go",
+	"service.go.tmpl":         "domain/service/{{.Name}}_service.go",
+	"service_test.go.tmpl":    "domain/service/{{.Name}}_service_test.go",
+	"request.go.tmpl":         "dto/request/{{.Name}}_request.go",
+	"response.go.tmpl":        "dto/response/{{.Name}}_response.go",
+	"handler.go.tmpl":         "handler/{{.Name}}_handler.go",
+}
+
+// Options configures the generator
+type Options struct {
+	// Root is the repository root containing go.mod and main.go
+	Root string
+	// Name is the module name, also used as package and directory name
+	Name string
+	// Register adds a blank import of the module to main.go so it self-registers
+	Register bool
+}
+
+// data is what the templates are rendered with
+type data struct {
+	ModulePath   string
+	Name         string
+	Package      string
+	Entity       string
+	EntityPlural string
+	Var          string
+	Table        string
+}
+
+// Generate writes a new module skeleton to <root>/modules/<name> and returns
+// the paths of the files it created
+func Generate(opts Options) ([]string, error) {
+	if !validName.MatchString(opts.Name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidName, opts.Name)
+	}
+	if reserved[opts.Name] {
+		return nil, fmt.Errorf("%w: %q", ErrReservedName, opts.Name)
+	}
+
+	modulePath, err := readModulePath(filepath.Join(opts.Root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(opts.Root, "modules", opts.Name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrModuleExists, dir)
+	}
+
+	entity := strings.ToUpper(opts.Name[:1]) + opts.Name[1:]
+	d := data{
+		ModulePath:   modulePath,
+		Name:         opts.Name,
+		Package:      opts.Name,
+		Entity:       entity,
+		EntityPlural: entity + "s",
+		Var:          opts.Name,
+		Table:        opts.Name + "s",
+	}
+
+	created := make([]string, 0, len(files))
+	for tmpl, target := range files {
+		path := filepath.Join(dir, strings.ReplaceAll(target, "{{.Name}}", opts.Name))
+		if err := render(tmpl, path, d); err != nil {
+			return created, err
+		}
+		created = append(created, path)
+	}
+
+	sort.Strings(created)
+
+	if opts.Register {
+		importPath := modulePath + "/modules/" + opts.Name
+		if err := addImport(filepath.Join(opts.Root, "main.go"), importPath); err != nil {
+			return created, err
+		}
+	}
+
+	return created, nil
+}
+
+// render executes a template, formats the result and writes it to path
+func render(name, path string, d data) error {
+	tmpl, err := template.ParseFS(templates, "templates/"+name)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, source, 0644)
+}
+
+// readModulePath returns the module path declared in go.mod
+func readModulePath(goMod string) (string, error) {
+	file, err := os.Open(goMod)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "module ")), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", ErrNoModulePath
+}
+
+// addImport adds a blank import of importPath after the last module import in main.go
+func addImport(mainFile, importPath string) error {
+	source, err := os.ReadFile(mainFile)
+	if err != nil {
+		return err
+	}
+
+	line := fmt.Sprintf("\t_ %q\n", importPath)
+	if bytes.Contains(source, []byte(line)) {
+		return nil
+	}
+
+	prefix := fmt.Sprintf("\t_ %q", filepath.Dir(importPath)+"/")
+	prefix = strings.TrimSuffix(prefix, `"`)
+
+	lines := strings.SplitAfter(string(source), "\n")
+	last := -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, prefix) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return ErrNoImportBlock
+	}
+
+	lines = append(lines[:last+1], append([]string{line}, lines[last+1:]...)...)
+
+	formatted, err := format.Source([]byte(strings.Join(lines, "")))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(mainFile, formatted, 0644)
+}
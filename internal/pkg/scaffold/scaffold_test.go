@@ -0,0 +1,86 @@
+package scaffold
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testMain = `package main
+
+import (
+	"example/internal/cli"
+	"os"
+
+	// modules register themselves with the application on import
+	_ "example/modules/auth"
+	_ "example/modules/users"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:]))
+}
+`
+
+func setupRoot(t *testing.T) string {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example\n\ngo 1.23\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(testMain), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestGenerate(t *testing.T) {
+	root := setupRoot(t)
+
+	created, err := Generate(Options{Root: root, Name: "invoice", Register: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created) != len(files) {
+		t.Fatalf("expected %d files, got %d", len(files), len(created))
+	}
+
+	fset := token.NewFileSet()
+	for _, path := range created {
+		file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("%s does not parse: %v", path, err)
+		}
+		for _, spec := range file.Imports {
+			if strings.Contains(spec.Path.Value, "go-modular") {
+				t.Errorf("%s imports %s instead of the module path from go.mod", path, spec.Path.Value)
+			}
+		}
+	}
+
+	main, _ := os.ReadFile(filepath.Join(root, "main.go"))
+	if !strings.Contains(string(main), `_ "example/modules/invoice"`) {
+		t.Errorf("module not registered in main.go:\n%s", main)
+	}
+
+	if _, err := Generate(Options{Root: root, Name: "invoice"}); !errors.Is(err, ErrModuleExists) {
+		t.Errorf("expected module exists error, got %v", err)
+	}
+}
+
+func TestGenerateRejectsInvalidNames(t *testing.T) {
+	root := setupRoot(t)
+
+	for _, name := range []string{"", "Invoice", "blog-post", "9lives"} {
+		if _, err := Generate(Options{Root: root, Name: name}); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("%q: expected invalid name error, got %v", name, err)
+		}
+	}
+
+	if _, err := Generate(Options{Root: root, Name: "service"}); !errors.Is(err, ErrReservedName) {
+		t.Errorf("expected reserved name error, got %v", err)
+	}
+}
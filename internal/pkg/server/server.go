@@ -27,10 +27,12 @@ type ServerContext struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+
+	server *http.Server
 }
 
 func NewServer(s ServerContext) IServer {
-	return ServerContext{
+	return &ServerContext{
 		Host:         s.Host,
 		CertFile:     s.CertFile,
 		KeyFile:      s.KeyFile,
@@ -41,24 +43,45 @@ func NewServer(s ServerContext) IServer {
 	}
 }
 
-func (s ServerContext) Run() {
+func (s *ServerContext) Run() {
 	// Set up a channel to listen to for interrupt signals
 	var runChan = make(chan os.Signal, 1)
 
+	// Handle ctrl+c/ctrl+x interrupt
+	signal.Notify(runChan, os.Interrupt, syscall.SIGTERM)
+
+	errChan := s.Start()
+
+	// Block on this channel listeninf for those previously defined syscalls assign
+	// to variable so we can let the user know why the server is shutting down
+	select {
+	case interrupt := <-runChan:
+		log.Printf("Server is shutting down due to %+v\n", interrupt)
+	case err := <-errChan:
+		log.Fatalf("Server failed to start due to err: %v", err)
+	}
+
 	// Set up a context to allow for graceful server shutdowns in the event
 	// of an OS interrupt (defers the cancel just in case)
-
 	ctx, cancel := context.WithTimeout(
 		context.Background(),
 		s.Timeout,
 	)
 	defer cancel()
 
+	if err := s.Shutdown(ctx); err != nil {
+		log.Fatalf("Server was unable to gracefully shutdown due to err: %+v", err)
+	}
+}
+
+// Start runs the server on a new goroutine and returns a channel that
+// receives the error if the server stops for any reason other than Shutdown
+func (s *ServerContext) Start() <-chan error {
 	// Define server options
-	server := &http.Server{
+	s.server = &http.Server{
 		Addr:         s.Host,
 		Handler:      s.Handler,
-		ReadTimeout:  s.Timeout * time.Second,
+		ReadTimeout:  s.ReadTimeout * time.Second,
 		WriteTimeout: s.WriteTimeout * time.Second,
 		IdleTimeout:  s.IdleTimeout * time.Second,
 	}
@@ -76,31 +99,27 @@ func (s ServerContext) Run() {
 	// info
 	log.Printf("Server Running on : %v", s.Host)
 
-	// Handle ctrl+c/ctrl+x interrupt
-	signal.Notify(runChan, os.Interrupt, syscall.SIGTERM)
-
-	// Run the server on a new goroutine
+	errChan := make(chan error, 1)
 	go func() {
-		if err := server.ListenAndServe(); err != nil {
+		if err := s.server.ListenAndServe(); err != nil {
 			if err != http.ErrServerClosed {
-				log.Fatalf("Server failed to start due to err: %v", err)
+				errChan <- err
 			}
 		}
 	}()
 
-	// Block on this channel listeninf for those previously defined syscalls assign
-	// to variable so we can let the user know why the server is shutting down
-	interrupt := <-runChan
-
-	// If we get one of the pre-prescribed syscalls, gracefully terminate the server
-	// while alerting the user
-	log.Fatalf("Server is shutting down due to %+v\n", interrupt)
+	return errChan
+}
 
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server was unable to gracefully shutdown due to err: %+v", err)
+// Shutdown gracefully stops the server, waiting for active connections
+// until the context expires
+func (s *ServerContext) Shutdown(ctx context.Context) error {
+	if s.server == nil {
+		return nil
 	}
+	return s.server.Shutdown(ctx)
 }
 
-func (s ServerContext) RunWithSSL() {
+func (s *ServerContext) RunWithSSL() {
 
 }
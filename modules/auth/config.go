@@ -0,0 +1,14 @@
+package auth
+
+// Config holds the [modules.auth] configuration section
+type Config struct {
+	SignatureKey string `mapstructure:"signature_key" validate:"required"`
+	DayExpired   int    `mapstructure:"day_expired" validate:"min=1"`
+}
+
+// defaultConfig returns the values used when a key is missing from the file
+func defaultConfig() Config {
+	return Config{
+		DayExpired: 7,
+	}
+}
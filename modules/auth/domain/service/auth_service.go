@@ -3,10 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"go-modular/internal/contract"
 	"go-modular/internal/pkg/jwt"
 	"go-modular/internal/pkg/utils"
-	"go-modular/modules/users/domain/entity"
-	"go-modular/modules/users/domain/repository"
 )
 
 // Errors
@@ -18,28 +17,28 @@ var (
 
 // AuthService handles user authentication
 type AuthService struct {
-	userRepo repository.UserRepository
-	jwt      jwt.JWT
+	users contract.UserPort
+	jwt   jwt.JWT
 }
 
 // NewAuthService creates a new AuthService
-func NewAuthService(userRepo repository.UserRepository) *AuthService {
-	if userRepo == nil {
-		panic("userRepo cannot be nil")
+func NewAuthService(users contract.UserPort) *AuthService {
+	if users == nil {
+		panic("users cannot be nil")
 	}
 	return &AuthService{
-		userRepo: userRepo,
+		users: users,
 	}
 }
 
 // CreateUser creates a new user
-func (s *AuthService) CreateUser(ctx context.Context, user *entity.User) error {
+func (s *AuthService) CreateUser(ctx context.Context, user *contract.User) error {
 	if user.Email == "" || user.Password == "" {
 		return errors.New("email and password cannot be empty")
 	}
 
-	existingUser, err := s.userRepo.FindByEmail(ctx, user.Email)
-	if err != nil && err != repository.ERR_RECORD_NOT_FOUND {
+	existingUser, err := s.users.FindByEmail(ctx, user.Email)
+	if err != nil && err != contract.ErrUserNotFound {
 		return err
 	}
 	if existingUser != nil {
@@ -53,20 +52,20 @@ func (s *AuthService) CreateUser(ctx context.Context, user *entity.User) error {
 	}
 	user.Password = hashedPassword
 
-	return s.userRepo.Create(ctx, user)
+	return s.users.Create(ctx, user)
 }
 
 // ProcessLogin handles user login and password verification
-func (s *AuthService) ProcessLogin(ctx context.Context, email, password string) (*entity.User, error) {
+func (s *AuthService) ProcessLogin(ctx context.Context, email, password string) (*contract.User, error) {
 	// Validate input
 	if email == "" || password == "" {
 		return nil, errors.New("email and password cannot be empty")
 	}
 
 	// Find user by email
-	existingUser, err := s.userRepo.FindByEmail(ctx, email)
+	existingUser, err := s.users.FindByEmail(ctx, email)
 	if err != nil {
-		if err == repository.ERR_RECORD_NOT_FOUND {
+		if err == contract.ErrUserNotFound {
 			return nil, ErrUserNotFound
 		}
 		return nil, err
@@ -81,7 +80,7 @@ func (s *AuthService) ProcessLogin(ctx context.Context, email, password string)
 	return existingUser, nil
 }
 
-func (s *AuthService) ChangePassword(ctx context.Context, userID uint, password string) (*entity.User, error) {
+func (s *AuthService) ChangePassword(ctx context.Context, userID uint, password string) (*contract.User, error) {
 	if password == "" {
 		return nil, errors.New("password cannot be empty")
 	}
@@ -91,14 +90,14 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID uint, password
 		return nil, errors.New("failed to hash password")
 	}
 
-	user, err := s.userRepo.FindByID(ctx, userID)
+	user, err := s.users.FindByID(ctx, userID)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
 
 	user.Password = hashedPassword
 
-	err = s.userRepo.Update(ctx, user)
+	err = s.users.Update(ctx, user)
 	if err != nil {
 		return nil, errors.New("failed to update password")
 	}
@@ -0,0 +1,26 @@
+package response
+
+import (
+	"go-modular/internal/contract"
+	"time"
+)
+
+// UserResponse represents the authenticated user in auth responses
+type UserResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FromUser converts a contract user to a user response
+func FromUser(user *contract.User) *UserResponse {
+	return &UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
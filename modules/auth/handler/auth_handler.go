@@ -1,15 +1,15 @@
 package handler
 
 import (
-	"fmt"
+	"go-modular/internal/contract"
 	"go-modular/internal/pkg/bus"
 	"go-modular/internal/pkg/jwt"
 	"go-modular/internal/pkg/logger"
+	"go-modular/internal/pkg/middleware"
 	"go-modular/internal/pkg/utils"
 	"go-modular/modules/auth/domain/service"
-	"go-modular/modules/users/domain/entity"
-	"go-modular/modules/users/dto/request"
-	"go-modular/modules/users/dto/response"
+	"go-modular/modules/auth/dto/request"
+	"go-modular/modules/auth/dto/response"
 	"net/http"
 
 	"github.com/labstack/echo"
@@ -35,16 +35,11 @@ func NewAuthHandler(log *logger.Logger, event *bus.EventBus, authService *servic
 	}
 }
 
-// Initialize Event Handle.
-func (h *AuthHandler) Handle(event bus.Event) {
-	fmt.Printf("User created: %v", event.Payload)
-}
-
 // Register handles user registration.
 func (h *AuthHandler) Register(c echo.Context) error {
 	h.log.Info("Handling register request")
 
-	req := new(request.CreateUserRequest)
+	req := new(request.RegisterRequest)
 	if err := c.Bind(req); err != nil {
 		h.log.Error("Failed to bind request:", err)
 		return h.r.ErrorResponse(c, http.StatusBadRequest, err.Error())
@@ -57,7 +52,7 @@ func (h *AuthHandler) Register(c echo.Context) error {
 
 	h.log.Debug("Request validated successfully:", req)
 
-	user := entity.NewUser(req.Name, req.Email, req.Password)
+	user := &contract.User{Name: req.Name, Email: req.Email, Password: req.Password}
 	err := h.authService.CreateUser(c.Request().Context(), user)
 	if err != nil {
 		if err == service.ErrEmailAlreadyUsed {
@@ -70,11 +65,8 @@ func (h *AuthHandler) Register(c echo.Context) error {
 
 	h.log.Debug("User created successfully:", user)
 
-	h.event.Publish(bus.Event{Type: "user.created", Payload: user})
-	h.log.Debug("Event 'user.created' published successfully")
-
 	return h.r.SuccessResponse(c, map[string]interface{}{
-		"user": response.FromEntity(user),
+		"user": response.FromUser(user),
 	}, "User registered successfully")
 }
 
@@ -121,13 +113,13 @@ func (h *AuthHandler) Login(c echo.Context) error {
 
 	return h.r.SuccessResponse(c, map[string]interface{}{
 		"token": token,
-		"user":  response.FromEntity(user),
+		"user":  response.FromUser(user),
 	}, "Login successful")
 }
 
 // RegisterRoutes sets up the auth routes.
 func (h *AuthHandler) RegisterRoutes(e *echo.Echo, basePath string) {
-	group := e.Group(basePath + "/auth")
+	group := middleware.Group(e, basePath+"/auth")
 	group.POST("/register", h.Register)
 	group.POST("/login", h.Login)
 }
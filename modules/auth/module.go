@@ -1,42 +1,64 @@
 package auth
 
 import (
+	"go-modular/internal/app"
+	"go-modular/internal/contract"
 	"go-modular/internal/pkg/bus"
-	"go-modular/internal/pkg/config"
+	"go-modular/internal/pkg/container"
+	"go-modular/internal/pkg/jwt"
 	"go-modular/internal/pkg/logger"
+	"go-modular/internal/pkg/middleware"
+	"go-modular/internal/pkg/migration"
 	"go-modular/modules/auth/domain/service"
 	"go-modular/modules/auth/handler"
-	"go-modular/modules/users/domain/repository"
 
 	"github.com/labstack/echo"
 	"gorm.io/gorm"
 )
 
+func init() {
+	app.Register("auth", func() app.Module { return NewModule() })
+}
+
 type Module struct {
 	db          *gorm.DB
 	logger      *logger.Logger
 	authService *service.AuthService
 	authHandler *handler.AuthHandler
 	event       *bus.EventBus
+	config      Config
 }
 
 func (m *Module) Name() string {
 	return "auth"
 }
 
-func (m *Module) Initialize(db *gorm.DB, log *logger.Logger, event *bus.EventBus) error {
+func (m *Module) Dependencies() []string {
+	return []string{"user"}
+}
+
+// Config returns the module's configuration section
+func (m *Module) Config() interface{} {
+	return &m.config
+}
+
+func (m *Module) Initialize(db *gorm.DB, log *logger.Logger, event *bus.EventBus, services *container.Container) error {
 	m.db = db
 	m.logger = log
 	m.event = event
 
-	// Initialize repositories
-	userRepo := repository.NewUserRepositoryImpl()
+	// Resolve the user port published by the user module
+	users, err := container.Resolve[contract.UserPort](services)
+	if err != nil {
+		return err
+	}
 
 	// Initialize services
-	m.authService = service.NewAuthService(userRepo)
+	m.authService = service.NewAuthService(users)
 
-	// Initialize JWT
-	jwtService := config.GetJWTService()
+	// Initialize JWT and share it with the Auth middleware
+	jwtService := jwt.NewJWTImpl(m.config.SignatureKey, m.config.DayExpired)
+	middleware.InitializeAuth(jwtService)
 
 	// Initialize handlers
 	m.authHandler = handler.NewAuthHandler(m.logger, m.event, m.authService, jwtService)
@@ -53,7 +75,7 @@ func (m *Module) RegisterRoutes(e *echo.Echo, basePath string) {
 	m.authHandler.RegisterRoutes(e, basePath)
 }
 
-func (m *Module) Migrations() error {
+func (m *Module) Migrations() []migration.Migration {
 	return nil
 }
 
@@ -62,5 +84,7 @@ func (m *Module) Logger() *logger.Logger {
 }
 
 func NewModule() *Module {
-	return &Module{}
+	return &Module{
+		config: defaultConfig(),
+	}
 }
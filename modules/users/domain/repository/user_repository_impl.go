@@ -15,18 +15,18 @@ type UserRepositoryImpl struct{}
 
 // Create implements UserRepository.
 func (r UserRepositoryImpl) Create(ctx context.Context, user *entity.User) error {
-	return database.DB.WithContext(ctx).Create(user).Error
+	return database.Conn(ctx).Create(user).Error
 }
 
 // Delete implements UserRepository.
 func (r UserRepositoryImpl) Delete(ctx context.Context, id uint) error {
-	return database.DB.WithContext(ctx).Delete(&entity.User{}, id).Error
+	return database.Conn(ctx).Delete(&entity.User{}, id).Error
 }
 
 // FindAll finds all users
 func (r UserRepositoryImpl) FindAll(ctx context.Context) ([]*entity.User, error) {
 	var users []*entity.User
-	result := database.DB.WithContext(ctx).Find(&users)
+	result := database.Conn(ctx).Find(&users)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -36,7 +36,7 @@ func (r UserRepositoryImpl) FindAll(ctx context.Context) ([]*entity.User, error)
 // FindByEmail implements UserRepository.
 func (r UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	result := database.DB.WithContext(ctx).Where("email = ?", email).First(&user)
+	result := database.Conn(ctx).Where("email = ?", email).First(&user)
 	if result.Error != nil {
 		if result.RowsAffected == 0 {
 			return nil, ERR_RECORD_NOT_FOUND
@@ -50,7 +50,7 @@ func (r UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (*ent
 // FindByID implements UserRepository.
 func (r UserRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.User, error) {
 	var user entity.User
-	result := database.DB.WithContext(ctx).First(&user, id)
+	result := database.Conn(ctx).First(&user, id)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -59,7 +59,7 @@ func (r UserRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.User
 
 // Update implements UserRepository.
 func (r UserRepositoryImpl) Update(ctx context.Context, user *entity.User) error {
-	return database.DB.WithContext(ctx).Save(user).Error
+	return database.Conn(ctx).Save(user).Error
 }
 
 func NewUserRepositoryImpl() UserRepository {
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-modular/internal/contract"
+	"go-modular/internal/pkg/bus"
+	"go-modular/modules/users/domain/entity"
+	"go-modular/modules/users/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// UserPort exposes the user repository to other modules as contract.UserPort
+type UserPort struct {
+	userRepo repository.UserRepository
+	event    *bus.EventBus
+}
+
+// NewUserPort creates a new user port
+func NewUserPort(userRepo repository.UserRepository, event *bus.EventBus) *UserPort {
+	return &UserPort{
+		userRepo: userRepo,
+		event:    event,
+	}
+}
+
+// FindByID finds a user by ID
+func (p *UserPort) FindByID(ctx context.Context, id uint) (*contract.User, error) {
+	user, err := p.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, portError(err)
+	}
+	return toContract(user), nil
+}
+
+// GetUser answers the contract.GetUser query
+func (p *UserPort) GetUser(ctx context.Context, query contract.GetUser) (*contract.User, error) {
+	return p.FindByID(ctx, query.ID)
+}
+
+// FindByEmail finds a user by email
+func (p *UserPort) FindByEmail(ctx context.Context, email string) (*contract.User, error) {
+	user, err := p.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, portError(err)
+	}
+	return toContract(user), nil
+}
+
+// Create stores a new user, records the user.created event and copies the
+// generated fields back
+func (p *UserPort) Create(ctx context.Context, user *contract.User) error {
+	record := fromContract(user)
+	if err := createUser(ctx, p.userRepo, p.event, record); err != nil {
+		return err
+	}
+	*user = *toContract(record)
+	return nil
+}
+
+// Update saves an existing user
+func (p *UserPort) Update(ctx context.Context, user *contract.User) error {
+	record := fromContract(user)
+	if err := p.userRepo.Update(ctx, record); err != nil {
+		return err
+	}
+	*user = *toContract(record)
+	return nil
+}
+
+// portError translates repository lookup errors to contract errors
+func portError(err error) error {
+	if errors.Is(err, repository.ERR_RECORD_NOT_FOUND) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return contract.ErrUserNotFound
+	}
+	return err
+}
+
+func toContract(user *entity.User) *contract.User {
+	return &contract.User{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		Password:  user.Password,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func fromContract(user *contract.User) *entity.User {
+	record := entity.NewUser(user.Name, user.Email, user.Password)
+	record.ID = user.ID
+	record.Role = user.Role
+	if !user.CreatedAt.IsZero() {
+		record.CreatedAt = user.CreatedAt
+	}
+	return record
+}
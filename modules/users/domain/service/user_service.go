@@ -3,6 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"go-modular/internal/contract"
+	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/database"
+	"go-modular/internal/pkg/outbox"
 	"go-modular/modules/users/domain/entity"
 	"go-modular/modules/users/domain/repository"
 )
@@ -16,12 +20,14 @@ var (
 // UserService handles user domain logic
 type UserService struct {
 	userRepo repository.UserRepository
+	event    *bus.EventBus
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository) *UserService {
+func NewUserService(userRepo repository.UserRepository, event *bus.EventBus) *UserService {
 	return &UserService{
 		userRepo: userRepo,
+		event:    event,
 	}
 }
 
@@ -52,7 +58,7 @@ func (s *UserService) CreateUser(ctx context.Context, user *entity.User) error {
 	// 	return ErrEmailAlreadyUsed
 	// }
 
-	return s.userRepo.Create(ctx, user) //
+	return createUser(ctx, s.userRepo, s.event, user)
 }
 
 // UpdateUser updates a user
@@ -80,3 +86,20 @@ func (s *UserService) DeleteUser(ctx context.Context, id uint) error {
 
 	return s.userRepo.Delete(ctx, id)
 }
+
+// createUser stores user and records the user.created event in the outbox
+// within one transaction, so the event exists exactly when the user does
+func createUser(ctx context.Context, userRepo repository.UserRepository, event *bus.EventBus, user *entity.User) error {
+	return database.Transaction(ctx, func(ctx context.Context) error {
+		if err := userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		return outbox.Enqueue(ctx, event, contract.UserCreatedTopic, contract.UserCreated{
+			ID:        user.ID,
+			Name:      user.Name,
+			Email:     user.Email,
+			Role:      user.Role,
+			CreatedAt: user.CreatedAt,
+		})
+	})
+}
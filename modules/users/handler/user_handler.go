@@ -3,7 +3,8 @@
 package handler
 
 import (
-	"fmt"
+	"context"
+	"go-modular/internal/contract"
 	"go-modular/internal/pkg/bus"
 	"go-modular/internal/pkg/logger"
 	"go-modular/internal/pkg/middleware"
@@ -33,9 +34,10 @@ func NewUserHandler(log *logger.Logger, event *bus.EventBus, userService *servic
 	}
 }
 
-// Event Bus Event user created
-func (h *UserHandler) Handle(event bus.Event) {
-	fmt.Printf("User created: %v", event.Payload)
+// OnUserCreated handles the user.created event
+func (h *UserHandler) OnUserCreated(ctx context.Context, event contract.UserCreated) error {
+	h.log.Info("User created", "id", event.ID, "email", event.Email)
+	return nil
 }
 
 // GetAllUsers gets all users
@@ -92,9 +94,6 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	// event bus publish
-	h.event.Publish(bus.Event{Type: "user.created", Payload: user})
-
 	return c.JSON(http.StatusCreated, response.FromEntity(user))
 }
 
@@ -160,7 +159,7 @@ func (h *UserHandler) DeleteUser(c echo.Context) error {
 
 // RegisterRoutes registers the user routes
 func (h *UserHandler) RegisterRoutes(e *echo.Echo, basePath string) {
-	group := e.Group(basePath+"/users", middleware.Auth)
+	group := middleware.Group(e, basePath+"/users", middleware.Auth)
 
 	group.GET("", h.GetAllUsers)
 	group.GET("/:id", h.GetUser)
@@ -0,0 +1,27 @@
+package user
+
+import (
+	"go-modular/internal/pkg/migration"
+	"go-modular/modules/users/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+// migrations lists the user module's schema changes; never edit a released
+// migration, append a new version instead
+func migrations() []migration.Migration {
+	return []migration.Migration{
+		{
+			// AutoMigrate keeps this first version safe on databases created
+			// before migrations were versioned
+			Version: 1,
+			Name:    "create_users_table",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.User{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&entity.User{})
+			},
+		},
+	}
+}
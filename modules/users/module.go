@@ -1,9 +1,12 @@
 package user
 
 import (
+	"go-modular/internal/app"
+	"go-modular/internal/contract"
 	"go-modular/internal/pkg/bus"
+	"go-modular/internal/pkg/container"
 	"go-modular/internal/pkg/logger"
-	"go-modular/modules/users/domain/entity"
+	"go-modular/internal/pkg/migration"
 	"go-modular/modules/users/domain/repository"
 	"go-modular/modules/users/domain/service"
 	"go-modular/modules/users/handler"
@@ -12,6 +15,10 @@ import (
 	"gorm.io/gorm"
 )
 
+func init() {
+	app.Register("user", func() app.Module { return NewModule() })
+}
+
 // Module implements the application Module interface for the user module
 type Module struct {
 	db          *gorm.DB
@@ -26,8 +33,13 @@ func (m *Module) Name() string {
 	return "user"
 }
 
+// Dependencies returns the modules this module depends on
+func (m *Module) Dependencies() []string {
+	return nil
+}
+
 // Initialize initializes the module
-func (m *Module) Initialize(db *gorm.DB, log *logger.Logger, event *bus.EventBus) error {
+func (m *Module) Initialize(db *gorm.DB, log *logger.Logger, event *bus.EventBus, services *container.Container) error {
 	m.db = db
 	m.logger = log
 	m.event = event
@@ -39,16 +51,35 @@ func (m *Module) Initialize(db *gorm.DB, log *logger.Logger, event *bus.EventBus
 	m.logger.Debug("User repository initialized")
 
 	// Initialize services
-	m.userService = service.NewUserService(userRepo)
+	m.userService = service.NewUserService(userRepo, m.event)
 	m.logger.Debug("User service initialized")
 
+	// Publish the user port for other modules
+	userPort := service.NewUserPort(userRepo, m.event)
+	if err := container.Provide[contract.UserPort](services, userPort); err != nil {
+		return err
+	}
+	m.logger.Debug("User port provided")
+
+	// Answer user queries from other modules
+	dispatcher, err := container.Resolve[*bus.Dispatcher](services)
+	if err != nil {
+		return err
+	}
+	if err := bus.Handle(dispatcher, userPort.GetUser); err != nil {
+		return err
+	}
+	m.logger.Debug("User query handlers registered")
+
 	// Initialize handlers
 	m.userHandler = handler.NewUserHandler(m.logger, m.event, m.userService)
 	m.logger.Debug("User handler initialized")
 
 	// register event listeners
 	m.logger.Info("Registering user module event listeners")
-	m.event.SubscribeFunc("user.created", m.userHandler.Handle)
+	if _, err := bus.Subscribe(m.event, contract.UserCreatedTopic, m.userHandler.OnUserCreated); err != nil {
+		return err
+	}
 
 	m.logger.Info("User module initialized successfully")
 	return nil
@@ -62,9 +93,8 @@ func (m *Module) RegisterRoutes(e *echo.Echo, basePath string) {
 }
 
 // Migrations returns the module's migrations
-func (m *Module) Migrations() error {
-	m.logger.Info("Registering user module migrations")
-	return m.db.AutoMigrate(&entity.User{})
+func (m *Module) Migrations() []migration.Migration {
+	return migrations()
 }
 
 // Logger returns the module's logger